@@ -1,47 +1,91 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
-	"path"
+	"path/filepath"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hedhyw/semerr/pkg/v1/semerr"
 
 	"github.com/hedhyw/json-log-viewer/internal/app"
 	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
 	"github.com/hedhyw/json-log-viewer/internal/pkg/events"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/export"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/session"
 	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
 )
 
+const (
+	// errUnknownView is returned if the view is not found in the config.
+	errUnknownView semerr.Error = "unknown view"
+	// errSchemaNotDetected is returned if the log has no JSON entries to
+	// write a config for.
+	errSchemaNotDetected semerr.Error = "columns are not detected"
+)
+
 // version will be set on build.
 var version = "development"
 
 const (
-	configFileName = ".jlv.jsonc"
+	configFileName = config.FileName
+	// xdgConfigFileName is a name of the config in the XDG config directory.
+	xdgConfigFileName = "config.jsonc"
 	// stdinFileName is displayed instead of a file name if the log is
 	// read from the standard input.
 	stdinFileName = "-"
+	// stdinSampleLines and stdinSampleTimeout limit the first lines of
+	// the standard input that columns are detected in, a stream that is
+	// slow to write them doesn't delay the start.
+	stdinSampleLines   = 100
+	stdinSampleTimeout = 500 * time.Millisecond
 )
 
 func main() {
 	configPath := flag.String("config", "", "Path to the config")
 	printVersion := flag.Bool("version", false, "Print version")
+	inputFormat := flag.String("format", "", "Input format: lines, json, csv or tsv")
+	printEntries := flag.Bool("print", false, "Print entries to stdout without the interactive view")
+	outputFormat := flag.String("output", string(export.FormatText), "Output format of -print: text, raw, csv, tsv or markdown")
+	filterTerm := flag.String("filter", "", "Print only entries that contain the term, \"/regex/\" is supported")
+	filterField := flag.String("filter-field", "", "Title of the column to filter by, all columns by default")
+	viewName := flag.String("view", "", "Name of the view of the config to open")
+	preset := flag.String("preset", "", "Built-in preset of columns: "+strings.Join(config.PresetNames(), ", "))
+	writeConfig := flag.String("write-config", "", "Write a starter config with columns detected in the log to the path")
 	flag.Parse()
 
+	// Sessions are not saved if the cache directory is unknown.
+	sessionDir, _ := session.DefaultDir()
+
 	err := runApp(applicationArguments{
 		Stdout: os.Stdout,
+		Stderr: os.Stderr,
 		Stdin:  os.Stdin,
 
 		ConfigPath:   *configPath,
+		Preset:       *preset,
 		PrintVersion: *printVersion,
+		InputFormat:  *inputFormat,
 		Args:         flag.Args(),
 
+		Print:        *printEntries,
+		OutputFormat: *outputFormat,
+		FilterTerm:   *filterTerm,
+		FilterField:  *filterField,
+		View:         *viewName,
+		WriteConfig:  *writeConfig,
+
+		SessionDir: sessionDir,
+
 		InterruptProcessGroup: interruptProcessGroup,
 
 		RunProgram: func(p *tea.Program) (tea.Model, error) {
@@ -56,12 +100,33 @@ func main() {
 
 type applicationArguments struct {
 	Stdout io.Writer
+	// Stderr receives warnings that don't fail the application.
+	Stderr io.Writer
 	Stdin  fs.File
 
-	ConfigPath   string
+	ConfigPath string
+	// Preset is the name of the built-in preset that overrides the preset
+	// of the config.
+	Preset       string
 	PrintVersion bool
+	InputFormat  string
 	Args         []string
 
+	// Print writes entries to Stdout instead of running the program.
+	Print        bool
+	OutputFormat string
+	FilterTerm   string
+	FilterField  string
+	// View is the name of the view of the config.
+	View string
+	// WriteConfig is a path to write a starter config to instead of running
+	// the program.
+	WriteConfig string
+
+	// SessionDir keeps sessions of opened files, it is empty if sessions are
+	// disabled.
+	SessionDir string
+
 	RunProgram            func(*tea.Program) (tea.Model, error)
 	InterruptProcessGroup func() error
 }
@@ -74,11 +139,45 @@ func runApp(args applicationArguments) (err error) {
 		return nil
 	}
 
-	cfg, err := readConfig(args.ConfigPath)
+	cfg, err := readConfig(args.ConfigPath, args.Preset, args.Args)
 	if err != nil {
 		return fmt.Errorf("reading config: %w", err)
 	}
 
+	if err := source.ValidateConfig(cfg); err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+
+	if args.View != "" {
+		if _, ok := cfg.View(args.View); !ok {
+			return fmt.Errorf("%w: %s", errUnknownView, args.View)
+		}
+	}
+
+	if args.WriteConfig != "" {
+		return writeStarterConfig(args)
+	}
+
+	profile, profileSelected := selectProfile(cfg, args.Args)
+
+	// Without a config, the columns are detected in the first entries if
+	// the default ones don't show their time, level or message.
+	inferColumns := !profileSelected && cfg.Path == config.PathDefault && cfg.Extends == ""
+
+	switch {
+	case profileSelected:
+		cfg = cfg.WithProfile(profile)
+	case inferColumns && len(args.Args) > 0:
+		useSchema(cfg, inferFileSchema(args.Args[0]))
+	}
+
+	switch {
+	case args.InputFormat != "":
+		cfg.InputFormat = config.InputFormat(args.InputFormat)
+	case cfg.InputFormat == "" && len(args.Args) == 1:
+		cfg.InputFormat = guessInputFormat(args.Args[0])
+	}
+
 	fileName := ""
 	stdinIsPipe := false
 
@@ -95,6 +194,13 @@ func runApp(args applicationArguments) (err error) {
 			return fmt.Errorf("getting stdin: %w", err)
 		}
 
+		if inferColumns {
+			var sample []byte
+
+			sample, stdin.Reader = sampleInput(stdin.Reader, stdinSampleLines, stdinSampleTimeout)
+			useSchema(cfg, source.InferSchema(bytes.NewReader(sample)))
+		}
+
 		inputSource, err = source.Reader(stdin.Reader, cfg)
 		if err != nil {
 			return fmt.Errorf("creating a temporary file: %w", err)
@@ -138,7 +244,33 @@ func runApp(args applicationArguments) (err error) {
 		defer func() { err = errors.Join(err, args.InterruptProcessGroup()) }()
 	}
 
-	appModel := app.NewModel(fileName, cfg, version)
+	if args.Print {
+		return printEntries(args, inputSource, cfg)
+	}
+
+	var (
+		sessionStore session.Store
+		sessionKey   string
+		options      []app.Option
+	)
+
+	if args.SessionDir != "" && len(args.Args) == 1 {
+		sessionStore = session.Store{Dir: args.SessionDir}
+
+		sessionKey, options, err = loadSession(sessionStore, fileName)
+		if err != nil {
+			return fmt.Errorf("loading session: %w", err)
+		}
+	}
+
+	if args.View != "" {
+		// It must follow the session, so that it replaces its filter.
+		options = append(options, app.WithView(args.View))
+	}
+
+	app.ApplyJSONTheme(cfg)
+
+	appModel := app.NewModel(fileName, cfg, version, options...)
 	program := tea.NewProgram(appModel, tea.WithInputTTY(), tea.WithAltScreen())
 
 	inputSource.StartStreaming(context.Background(), func(entries source.LazyLogEntries, err error) {
@@ -149,13 +281,95 @@ func runApp(args applicationArguments) (err error) {
 		}
 	})
 
-	if _, err := args.RunProgram(program); err != nil {
+	finalModel, err := args.RunProgram(program)
+	if err != nil {
 		return fmt.Errorf("running program: %w", err)
 	}
 
+	if state, ok := app.SessionState(finalModel); ok && sessionKey != "" {
+		// The log has already been viewed, so a session that can't be
+		// saved is not a failure of the application.
+		if err := sessionStore.Save(sessionKey, state); err != nil && args.Stderr != nil {
+			fmt.Fprintln(args.Stderr, "Warning: saving session: "+err.Error())
+		}
+	}
+
 	return nil
 }
 
+// loadSession returns the key of the session of the file and options that
+// restore the previous session.
+func loadSession(store session.Store, fileName string) (string, []app.Option, error) {
+	key, err := session.Key(fileName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	state, ok, err := store.Load(key)
+	if err != nil || !ok {
+		// A broken session is overwritten on exit.
+		return key, nil, nil //nolint:nilerr // The previous session is optional.
+	}
+
+	return key, []app.Option{app.WithSession(state)}, nil
+}
+
+// printEntries reads all entries and writes the matching ones to the standard
+// output in the requested format.
+func printEntries(args applicationArguments, inputSource *source.Source, cfg *config.Config) error {
+	outputFormat := args.OutputFormat
+	if outputFormat == "" {
+		outputFormat = string(export.FormatText)
+	}
+
+	format, err := export.ParseFormat(outputFormat)
+	if err != nil {
+		return fmt.Errorf("parsing output format: %w", err)
+	}
+
+	entries, err := inputSource.ParseLogEntries()
+	if err != nil {
+		return fmt.Errorf("parsing log entries: %w", err)
+	}
+
+	filterTerm, filterField := args.FilterTerm, args.FilterField
+
+	var order config.Sort
+
+	if view, ok := cfg.View(args.View); ok {
+		cfg = cfg.WithView(view)
+
+		if filterTerm == "" {
+			filterTerm, filterField = view.Filter, view.FilterField
+		}
+
+		if view.MinLevel != "" {
+			entries, err = entries.FilterByLevel(source.Level(strings.ToLower(view.MinLevel)), cfg)
+			if err != nil {
+				return fmt.Errorf("filtering by level: %w", err)
+			}
+		}
+
+		if view.Sort != nil {
+			order = *view.Sort
+		}
+	}
+
+	entries, err = entries.Filter(filterTerm, filterField, cfg)
+	if err != nil {
+		return fmt.Errorf("filtering: %w", err)
+	}
+
+	if !order.IsZero() {
+		entries, err = entries.Sort(order, cfg)
+		if err != nil {
+			return fmt.Errorf("sorting: %w", err)
+		}
+	}
+
+	return app.Print(args.Stdout, entries, cfg, format)
+}
+
 // logFiles reads the content of several files one after another.
 type logFiles struct {
 	reader io.Reader
@@ -206,24 +420,214 @@ func openLogFiles(names []string) (*logFiles, error) {
 	return logFiles, nil
 }
 
-// readConfig tries to read config from working directory or home directory.
-// If configs are not found, then it returns a default configuration.
-func readConfig(configPath string) (*config.Config, error) {
+// guessInputFormat returns the input format by the extension of the file.
+// Files with the extension ".json" are not guessed, because they often hold
+// an entry per line.
+func guessInputFormat(fileName string) config.InputFormat {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return config.InputFormatCSV
+	case ".tsv":
+		return config.InputFormatTSV
+	default:
+		return ""
+	}
+}
+
+// writeStarterConfig writes the default config with columns that are
+// detected in the log. The log is read from the first file or from the
+// standard input. An existing file is not overwritten.
+func writeStarterConfig(args applicationArguments) (err error) {
+	var schema source.Schema
+
+	if len(args.Args) > 0 {
+		schema = inferFileSchema(args.Args[0])
+	} else {
+		schema = source.InferSchema(args.Stdin)
+	}
+
+	if schema.IsEmpty() {
+		return fmt.Errorf("%w: %s", errSchemaNotDetected, args.WriteConfig)
+	}
+
+	cfg := config.GetDefaultConfig()
+	cfg.Fields = schema.Fields()
+
+	content, err := json.MarshalIndent(cfg, "", "    ")
+	if err != nil {
+		return fmt.Errorf("encoding config: %w", err)
+	}
+
+	file, err := os.OpenFile(args.WriteConfig, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("creating config: %w", err)
+	}
+
+	defer func() { err = errors.Join(err, file.Close()) }()
+
+	_, err = fmt.Fprintf(file, "// Columns are detected in the log, see docs/customization.md.\n%s\n", content)
+	if err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+
+	// nolint: forbidigo // Result of the command.
+	fmt.Fprintln(args.Stdout, "Config is written to "+args.WriteConfig)
+
+	return nil
+}
+
+func inferFileSchema(fileName string) source.Schema {
+	file, err := os.Open(fileName)
+	if err != nil {
+		// The error is reported when the log is read.
+		return source.Schema{}
+	}
+
+	defer func() { _ = file.Close() }()
+
+	return source.InferSchema(file)
+}
+
+// useSchema replaces the columns of the config with the detected ones if
+// the columns don't show the time, the level or the message of the log.
+func useSchema(cfg *config.Config, schema source.Schema) {
+	if !schema.IsEmpty() && !schema.IsCoveredBy(cfg.Fields) {
+		cfg.Fields = schema.Fields()
+	}
+}
+
+// selectProfile returns the profile that matches all log files by the file
+// name or by the keys of the first entry. Otherwise, the columns of the config
+// are kept and entries are matched to profiles by keys one by one.
+func selectProfile(cfg *config.Config, fileNames []string) (config.Profile, bool) {
+	if len(cfg.Profiles) == 0 || len(fileNames) == 0 {
+		return config.Profile{}, false
+	}
+
+	var selected config.Profile
+
+	for i, fileName := range fileNames {
+		profile, ok := cfg.ProfileForFile(fileName)
+		if !ok {
+			profile, ok = detectFileProfile(fileName, cfg)
+		}
+
+		if !ok || (i > 0 && profile.Name != selected.Name) {
+			return config.Profile{}, false
+		}
+
+		selected = profile
+	}
+
+	return selected, true
+}
+
+func detectFileProfile(fileName string, cfg *config.Config) (config.Profile, bool) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		// The error is reported when the log is read.
+		return config.Profile{}, false
+	}
+
+	defer func() { _ = file.Close() }()
+
+	return source.DetectProfile(file, cfg)
+}
+
+// readConfig reads and merges the configs that are found. If configs are not
+// found, then it returns a default configuration.
+func readConfig(configPath string, preset string, fileNames []string) (*config.Config, error) {
+	dirs := make([]string, 0, len(fileNames)+1)
+
+	for _, fileName := range fileNames {
+		dirs = append(dirs, filepath.Dir(fileName))
+	}
+
+	workDir, err := os.Getwd()
+	if err == nil {
+		dirs = append(dirs, workDir)
+	}
+
+	// The home directory is optional.
+	homeDir, _ := os.UserHomeDir()
+
+	paths := configSearchPaths(configPath, dirs, homeDir, os.Getenv("XDG_CONFIG_HOME"))
+
+	return config.ReadPreset(preset, paths...)
+}
+
+// configSearchPaths returns paths of configs from higher priority to lower
+// priority:
+//   - the config given by the flag;
+//   - `.jlv.jsonc` in the given directories and in their parents up to the
+//     repository root or the home directory, nearer directories have higher
+//     priority;
+//   - `.jlv.jsonc` in the home directory;
+//   - `jlv/config.jsonc` in the XDG config directory.
+func configSearchPaths(configPath string, dirs []string, homeDir string, xdgConfigHome string) []string {
 	paths := []string{}
 
 	if configPath != "" {
 		paths = append(paths, configPath)
 	}
 
-	workDir, err := os.Getwd()
-	if err == nil {
-		paths = append(paths, path.Join(workDir, configFileName))
+	for _, dir := range dirs {
+		paths = append(paths, projectConfigPaths(dir, homeDir)...)
 	}
 
-	homeDir, err := os.UserHomeDir()
-	if err == nil {
-		paths = append(paths, path.Join(homeDir, configFileName))
+	if homeDir != "" {
+		paths = append(paths, filepath.Join(homeDir, configFileName))
+	}
+
+	// Relative paths are invalid according to the XDG specification.
+	if !filepath.IsAbs(xdgConfigHome) && homeDir != "" {
+		xdgConfigHome = filepath.Join(homeDir, ".config")
 	}
 
-	return config.Read(paths...)
+	if filepath.IsAbs(xdgConfigHome) {
+		paths = append(paths, filepath.Join(xdgConfigHome, "jlv", xdgConfigFileName))
+	}
+
+	// The same config can be found from several directories, it is kept at
+	// its highest priority.
+	unique := make([]string, 0, len(paths))
+	seen := make(map[string]struct{}, len(paths))
+
+	for _, p := range paths {
+		if _, ok := seen[p]; ok {
+			continue
+		}
+
+		seen[p] = struct{}{}
+		unique = append(unique, p)
+	}
+
+	return unique
+}
+
+// projectConfigPaths returns paths of configs in the directory and its
+// parents up to the repository root that contains `.git` or up to the home
+// directory. Only the config of the directory is returned if the directory
+// is outside of them, so configs of shared parents, like /tmp, are not read.
+func projectConfigPaths(dir string, homeDir string) []string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+
+	paths := []string{filepath.Join(dir, configFileName)}
+
+	for parent := dir; ; {
+		if _, err := os.Stat(filepath.Join(parent, ".git")); err == nil || parent == filepath.Clean(homeDir) {
+			return paths
+		}
+
+		next := filepath.Dir(parent)
+		if next == parent {
+			return paths[:1]
+		}
+
+		parent = next
+		paths = append(paths, filepath.Join(parent, configFileName))
+	}
 }
@@ -6,11 +6,17 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/hedhyw/json-log-viewer/internal/app"
 	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/events"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/session"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
 	"github.com/hedhyw/json-log-viewer/internal/pkg/tests"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -62,6 +68,38 @@ func TestRunAppRunProgramReadConfigInvalid(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestRunAppRunProgramHighlightInvalid(t *testing.T) {
+	t.Parallel()
+
+	configPath := tests.RequireCreateFile(t, []byte(`{"highlights": [{"term": "/(/"}]}`))
+
+	err := runApp(applicationArguments{
+		ConfigPath: configPath,
+		RunProgram: func(*tea.Program) (tea.Model, error) {
+			t.Fatal("Should not run")
+
+			return app.NewModel("", config.GetDefaultConfig(), version), nil
+		},
+	})
+	require.ErrorIs(t, err, source.ErrInvalidHighlight)
+}
+
+func TestRunAppRunProgramTemplateInvalid(t *testing.T) {
+	t.Parallel()
+
+	configPath := tests.RequireCreateFile(t, []byte(`{"fields": [{"title": "Request", "kind": "any", "template": "{{ .method "}]}`))
+
+	err := runApp(applicationArguments{
+		ConfigPath: configPath,
+		RunProgram: func(*tea.Program) (tea.Model, error) {
+			t.Fatal("Should not run")
+
+			return app.NewModel("", config.GetDefaultConfig(), version), nil
+		},
+	})
+	require.ErrorIs(t, err, source.ErrInvalidTemplate)
+}
+
 func TestRunAppReadMultipleFilesNotFound(t *testing.T) {
 	t.Parallel()
 
@@ -152,6 +190,229 @@ func TestRunAppReadFileSuccess(t *testing.T) {
 	assert.True(t, isStarted)
 }
 
+func TestRunAppInputFormatInvalid(t *testing.T) {
+	t.Parallel()
+
+	fileName := tests.RequireCreateFile(t, []byte(t.Name()))
+
+	err := runApp(applicationArguments{
+		Args:        []string{fileName},
+		InputFormat: "xml",
+		RunProgram: func(*tea.Program) (tea.Model, error) {
+			t.Fatal("Should not run")
+
+			return app.NewModel("", config.GetDefaultConfig(), version), nil
+		},
+	})
+	require.Error(t, err)
+}
+
+func TestRunAppPrint(t *testing.T) {
+	t.Parallel()
+
+	const content = `{"time":"1970-01-01T00:00:00.00","level":"INFO","message":"first"}
+{"time":"1970-01-01T00:00:01.00","level":"ERROR","message":"second"}
+`
+
+	runPrint := func(t *testing.T, outputFormat string, filterTerm string) (string, error) {
+		t.Helper()
+
+		fileName := tests.RequireCreateFile(t, []byte(content))
+
+		var outputBuf bytes.Buffer
+
+		err := runApp(applicationArguments{
+			Stdout:       &outputBuf,
+			Args:         []string{fileName},
+			Print:        true,
+			OutputFormat: outputFormat,
+			FilterTerm:   filterTerm,
+			RunProgram: func(*tea.Program) (tea.Model, error) {
+				t.Fatal("Should not run")
+
+				return app.NewModel("", config.GetDefaultConfig(), version), nil
+			},
+		})
+
+		return outputBuf.String(), err
+	}
+
+	t.Run("text", func(t *testing.T) {
+		t.Parallel()
+
+		output, err := runPrint(t, "", "")
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		require.Len(t, lines, 2)
+		assert.Contains(t, lines[0], "first")
+		assert.Contains(t, lines[1], "second")
+		assert.Equal(t, strings.Index(lines[0], "first"), strings.Index(lines[1], "second"))
+	})
+
+	t.Run("raw_filtered", func(t *testing.T) {
+		t.Parallel()
+
+		output, err := runPrint(t, "raw", "error")
+		require.NoError(t, err)
+
+		assert.Equal(t, strings.Split(content, "\n")[1]+"\n", output)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		t.Parallel()
+
+		output, err := runPrint(t, "csv", "/^.*first/")
+		require.NoError(t, err)
+
+		assert.Equal(t, "Time,Level,Message\n1970-01-01T00:00:00.00,info,first\n", output)
+	})
+
+	t.Run("invalid_format", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := runPrint(t, "xml", "")
+		require.Error(t, err)
+	})
+
+	t.Run("invalid_filter", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := runPrint(t, "", "/(/")
+		require.Error(t, err)
+	})
+}
+
+func TestRunAppView(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	cfg.Views = []config.View{{Name: "errors", MinLevel: "error"}, {Name: "upper", MinLevel: "ERROR"}}
+
+	configPath := tests.RequireCreateFile(t, tests.RequireEncodeJSON(t, cfg))
+	fileName := tests.RequireCreateFile(t, []byte(`{"level":"info","message":"first"}
+{"level":"error","message":"second"}
+`))
+
+	for _, view := range []string{"errors", "upper"} {
+		t.Run("print_"+view, func(t *testing.T) {
+			t.Parallel()
+
+			var outputBuf bytes.Buffer
+
+			err := runApp(applicationArguments{
+				Stdout:       &outputBuf,
+				ConfigPath:   configPath,
+				Args:         []string{fileName},
+				Print:        true,
+				OutputFormat: "raw",
+				View:         view,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, `{"level":"error","message":"second"}`+"\n", outputBuf.String())
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+
+		err := runApp(applicationArguments{
+			ConfigPath: configPath,
+			Args:       []string{fileName},
+			View:       "unknown",
+			RunProgram: func(*tea.Program) (tea.Model, error) {
+				t.Fatal("Should not run")
+
+				return app.NewModel("", config.GetDefaultConfig(), version), nil
+			},
+		})
+		require.ErrorIs(t, err, errUnknownView)
+	})
+}
+
+func TestRunAppSession(t *testing.T) {
+	t.Parallel()
+
+	fileName := tests.RequireCreateFile(t, []byte(`{"message":"first"}`+"\n"))
+	sessionDir := t.TempDir()
+
+	run := func() {
+		err := runApp(applicationArguments{
+			Args:       []string{fileName},
+			SessionDir: sessionDir,
+			RunProgram: func(*tea.Program) (tea.Model, error) {
+				model := app.NewModel("", config.GetDefaultConfig(), version)
+				model, _ = model.Update(events.LogEntriesUpdateMsg{})
+
+				return model, nil
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	run()
+
+	key, err := session.Key(fileName)
+	require.NoError(t, err)
+
+	_, ok, err := session.Store{Dir: sessionDir}.Load(key)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// The saved session is loaded.
+	run()
+
+	// A broken session is ignored.
+	require.NoError(t, os.WriteFile(filepath.Join(sessionDir, key+".json"), []byte("-"), 0o600))
+	run()
+
+	t.Run("save_failed", func(t *testing.T) {
+		t.Parallel()
+
+		var stderr bytes.Buffer
+
+		err := runApp(applicationArguments{
+			Stderr: &stderr,
+			Args:   []string{fileName},
+			// The directory can't be created inside the file.
+			SessionDir: filepath.Join(fileName, "sessions"),
+			RunProgram: func(*tea.Program) (tea.Model, error) {
+				model := app.NewModel("", config.GetDefaultConfig(), version)
+				model, _ = model.Update(events.LogEntriesUpdateMsg{})
+
+				return model, nil
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Contains(t, stderr.String(), "saving session")
+	})
+}
+
+func TestGuessInputFormat(t *testing.T) {
+	t.Parallel()
+
+	testCases := [...]struct {
+		FileName string
+		Expected config.InputFormat
+	}{
+		{FileName: "export.csv", Expected: config.InputFormatCSV},
+		{FileName: "/tmp/EXPORT.TSV", Expected: config.InputFormatTSV},
+		{FileName: "app.json", Expected: ""},
+		{FileName: "app.log", Expected: ""},
+		{FileName: "-", Expected: ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.FileName, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.Expected, guessInputFormat(testCase.FileName))
+		})
+	}
+}
+
 func TestRunAppReadFileNotFound(t *testing.T) {
 	t.Parallel()
 
@@ -360,6 +621,57 @@ func TestGetStdinSource(t *testing.T) {
 	})
 }
 
+func TestSampleInput(t *testing.T) {
+	t.Parallel()
+
+	t.Run("max_lines", func(t *testing.T) {
+		t.Parallel()
+
+		sample, reader := sampleInput(strings.NewReader("1\n2\n3\n4"), 2, time.Minute)
+		assert.Equal(t, "1\n2\n", string(sample))
+
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "1\n2\n3\n4", string(data))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+
+		pipeReader, pipeWriter := io.Pipe()
+
+		go func() {
+			_, _ = pipeWriter.Write([]byte("1\n"))
+			_, _ = pipeWriter.Write([]byte("2\n"))
+		}()
+
+		sample, reader := sampleInput(pipeReader, 10, 500*time.Millisecond)
+		assert.Equal(t, "1\n2\n", string(sample))
+
+		// The stream continues after the sample.
+		go func() {
+			_, _ = pipeWriter.Write([]byte("3\n"))
+			_ = pipeWriter.Close()
+		}()
+
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "1\n2\n3\n", string(data))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		// nolint: err113 // Test.
+		errRead := errors.New(t.Name())
+
+		_, reader := sampleInput(io.MultiReader(strings.NewReader("1\n"), iotest.ErrReader(errRead)), 10, time.Minute)
+
+		_, err := io.ReadAll(reader)
+		require.ErrorIs(t, err, errRead)
+	})
+}
+
 type fakeFile struct {
 	io.Closer
 	io.Reader
@@ -382,3 +694,353 @@ type fakeFileInfo struct {
 func (f fakeFileInfo) Mode() fs.FileMode {
 	return f.FileMode
 }
+
+func TestConfigSearchPaths(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	serviceDir := filepath.Join(root, "service")
+	logsDir := filepath.Join(serviceDir, "logs")
+
+	require.NoError(t, os.MkdirAll(logsDir, 0o700))
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0o700))
+
+	t.Run("repository", func(t *testing.T) {
+		t.Parallel()
+
+		paths := configSearchPaths("custom.jsonc", []string{logsDir, serviceDir}, "/home/user", "/xdg")
+
+		assert.Equal(t, []string{
+			"custom.jsonc",
+			filepath.Join(logsDir, configFileName),
+			filepath.Join(serviceDir, configFileName),
+			filepath.Join(root, configFileName),
+			filepath.Join("/home/user", configFileName),
+			filepath.Join("/xdg", "jlv", xdgConfigFileName),
+		}, paths)
+	})
+
+	t.Run("outside_repository", func(t *testing.T) {
+		t.Parallel()
+
+		dir := filepath.Join(t.TempDir(), "logs")
+		require.NoError(t, os.Mkdir(dir, 0o700))
+
+		paths := configSearchPaths("", []string{dir}, "", "")
+
+		assert.Equal(t, []string{filepath.Join(dir, configFileName)}, paths)
+	})
+
+	t.Run("home", func(t *testing.T) {
+		t.Parallel()
+
+		homeDir := t.TempDir()
+		dir := filepath.Join(homeDir, "logs")
+		require.NoError(t, os.Mkdir(dir, 0o700))
+
+		paths := configSearchPaths("", []string{dir}, homeDir, "/xdg")
+
+		assert.Equal(t, []string{
+			filepath.Join(dir, configFileName),
+			filepath.Join(homeDir, configFileName),
+			filepath.Join("/xdg", "jlv", xdgConfigFileName),
+		}, paths)
+	})
+
+	t.Run("xdg_default", func(t *testing.T) {
+		t.Parallel()
+
+		paths := configSearchPaths("", nil, "/home/user", "relative")
+
+		assert.Equal(t, []string{
+			filepath.Join("/home/user", configFileName),
+			filepath.Join("/home/user", ".config", "jlv", xdgConfigFileName),
+		}, paths)
+	})
+
+	t.Run("no_home", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, configSearchPaths("", nil, "", ""))
+	})
+}
+
+func TestRunAppProjectConfig(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	logsDir := filepath.Join(root, "logs")
+
+	require.NoError(t, os.Mkdir(logsDir, 0o700))
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0o700))
+
+	// The view is declared in the repository root, the directory of the log
+	// overrides only the columns.
+	rootConfig := `{"views": [{"name": "errors", "minLevel": "error"}]}`
+	logsConfig := `{"fields": [
+		{"title": "Level", "kind": "level", "ref": ["$.level"]},
+		{"title": "Text", "kind": "message", "ref": ["$.message"]}
+	]}`
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, configFileName), []byte(rootConfig), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(logsDir, configFileName), []byte(logsConfig), 0o600))
+
+	fileName := filepath.Join(logsDir, "app.log")
+	content := `{"level":"info","message":"first"}` + "\n" + `{"level":"error","message":"second"}` + "\n"
+
+	require.NoError(t, os.WriteFile(fileName, []byte(content), 0o600))
+
+	var outputBuf bytes.Buffer
+
+	err := runApp(applicationArguments{
+		Stdout:       &outputBuf,
+		Args:         []string{fileName},
+		Print:        true,
+		OutputFormat: "csv",
+		View:         "errors",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Level,Text\nerror,second\n", outputBuf.String())
+}
+
+func TestRunAppPrintSortedView(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	cfg := `{"views": [{"name": "slow", "sort": {"field": "$.duration", "descending": true}}]}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, configFileName), []byte(cfg), 0o600))
+
+	fileName := filepath.Join(dir, "app.log")
+	content := `{"message":"fast","duration":5}` + "\n" +
+		`{"message":"slow","duration":100}` + "\n" +
+		`{"message":"none"}` + "\n"
+
+	require.NoError(t, os.WriteFile(fileName, []byte(content), 0o600))
+
+	var outputBuf bytes.Buffer
+
+	err := runApp(applicationArguments{
+		Stdout:       &outputBuf,
+		Args:         []string{fileName},
+		Print:        true,
+		OutputFormat: "ndjson",
+		View:         "slow",
+	})
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(outputBuf.String()), "\n")
+	require.Len(t, lines, 3, outputBuf.String())
+	assert.Contains(t, lines[0], "slow")
+	assert.Contains(t, lines[1], "fast")
+	assert.Contains(t, lines[2], "none")
+}
+
+func TestRunAppProfiles(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	cfg.Fields = []config.Field{{
+		Title:      "Message",
+		Kind:       config.FieldKindMessage,
+		References: []string{"$.message"},
+	}}
+	cfg.Profiles = []config.Profile{{
+		Name:  "nginx",
+		Match: config.ProfileMatch{Files: []string{"nginx-*.log"}},
+		Fields: []config.Field{{
+			Title:      "Path",
+			Kind:       config.FieldKindAny,
+			References: []string{"$.path"},
+		}},
+	}, {
+		Name:  "gcp",
+		Match: config.ProfileMatch{Keys: []string{"$.severity"}},
+		Fields: []config.Field{{
+			Title:      "Severity",
+			Kind:       config.FieldKindLevel,
+			References: []string{"$.severity"},
+		}, {
+			Title:      "Message",
+			Kind:       config.FieldKindMessage,
+			References: []string{"$.textPayload"},
+		}},
+	}}
+
+	configPath := tests.RequireCreateFile(t, tests.RequireEncodeJSON(t, cfg))
+
+	dir := t.TempDir()
+	nginxFile := filepath.Join(dir, "nginx-access.log")
+	gcpFile := filepath.Join(dir, "cloud.log")
+	appFile := filepath.Join(dir, "app.log")
+
+	require.NoError(t, os.WriteFile(nginxFile, []byte(`{"path":"/health"}`+"\n"), 0o600))
+	require.NoError(t, os.WriteFile(gcpFile, []byte(`{"severity":"ERROR","textPayload":"cloud"}`+"\n"), 0o600))
+	require.NoError(t, os.WriteFile(appFile, []byte(`{"message":"app"}`+"\n"), 0o600))
+
+	testCases := [...]struct {
+		Name     string
+		Files    []string
+		Expected string
+	}{{
+		Name:     "file_name",
+		Files:    []string{nginxFile},
+		Expected: "Path\n/health\n",
+	}, {
+		Name:     "keys",
+		Files:    []string{gcpFile},
+		Expected: "Severity,Message\nerror,cloud\n",
+	}, {
+		Name:     "merged",
+		Files:    []string{appFile, gcpFile},
+		Expected: "Message\napp\ncloud\n",
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			var outputBuf bytes.Buffer
+
+			err := runApp(applicationArguments{
+				Stdout:       &outputBuf,
+				ConfigPath:   configPath,
+				Args:         testCase.Files,
+				Print:        true,
+				OutputFormat: "csv",
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.Expected, outputBuf.String())
+		})
+	}
+}
+
+func TestRunAppInferColumns(t *testing.T) {
+	t.Parallel()
+
+	const serilogLog = `{"@t":"2026-01-01T00:00:00Z","@mt":"started","@l":"Warning"}` + "\n"
+
+	fileName := tests.RequireCreateFile(t, []byte(serilogLog))
+
+	t.Run("print", func(t *testing.T) {
+		t.Parallel()
+
+		var outputBuf bytes.Buffer
+
+		err := runApp(applicationArguments{
+			Stdout:       &outputBuf,
+			Args:         []string{fileName},
+			Print:        true,
+			OutputFormat: "csv",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Time,Level,Message\n2026-01-01T00:00:00Z,warn,started\n", outputBuf.String())
+	})
+
+	t.Run("stdin", func(t *testing.T) {
+		t.Parallel()
+
+		var outputBuf bytes.Buffer
+
+		err := runApp(applicationArguments{
+			Stdout: &outputBuf,
+			Stdin: fakeFile{
+				Reader:       strings.NewReader(serilogLog + serilogLog),
+				StatFileInfo: fakeFileInfo{FileMode: os.ModeNamedPipe},
+			},
+			Print:        true,
+			OutputFormat: "csv",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Time,Level,Message\n"+
+			"2026-01-01T00:00:00Z,warn,started\n"+
+			"2026-01-01T00:00:00Z,warn,started\n", outputBuf.String())
+	})
+
+	t.Run("config_exists", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := tests.RequireCreateFile(t, tests.RequireEncodeJSON(t, config.GetDefaultConfig()))
+
+		var outputBuf bytes.Buffer
+
+		err := runApp(applicationArguments{
+			Stdout:       &outputBuf,
+			ConfigPath:   configPath,
+			Args:         []string{fileName},
+			Print:        true,
+			OutputFormat: "csv",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Time,Level,Message\n-,-,-\n", outputBuf.String())
+	})
+
+	t.Run("write_config", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := filepath.Join(t.TempDir(), configFileName)
+
+		args := applicationArguments{
+			Stdout:      io.Discard,
+			Args:        []string{fileName},
+			WriteConfig: configPath,
+		}
+
+		require.NoError(t, runApp(args))
+
+		cfg, err := config.Read(configPath)
+		require.NoError(t, err)
+
+		if assert.Len(t, cfg.Fields, 3) {
+			assert.Equal(t, []string{`$["@l"]`}, cfg.Fields[1].References)
+		}
+
+		// An existing config is not overwritten.
+		require.ErrorIs(t, runApp(args), os.ErrExist)
+	})
+
+	t.Run("write_config_not_detected", func(t *testing.T) {
+		t.Parallel()
+
+		err := runApp(applicationArguments{
+			Stdin: fakeFile{
+				Reader:       strings.NewReader("plain text"),
+				StatFileInfo: fakeFileInfo{FileMode: os.ModeNamedPipe},
+			},
+			WriteConfig: filepath.Join(t.TempDir(), configFileName),
+		})
+		require.ErrorIs(t, err, errSchemaNotDetected)
+	})
+}
+
+func TestRunAppPreset(t *testing.T) {
+	t.Parallel()
+
+	fileName := tests.RequireCreateFile(t, []byte(`{"level":30,"time":1700000000000,"msg":"started"}`+"\n"))
+
+	run := func(preset string) (string, error) {
+		var outputBuf bytes.Buffer
+
+		err := runApp(applicationArguments{
+			Stdout:       &outputBuf,
+			Preset:       preset,
+			Args:         []string{fileName},
+			Print:        true,
+			OutputFormat: "csv",
+		})
+
+		return outputBuf.String(), err
+	}
+
+	output, err := run(config.PresetPino)
+	require.NoError(t, err)
+	assert.Equal(t, "Time,Level,Message\n2023-11-14T22:13:20Z,info,started\n", output)
+
+	_, err = run("unknown")
+	require.ErrorIs(t, err, config.ErrUnknownPreset)
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
+	"time"
 )
 
 // stdinInput is a log source that is read from the standard input.
@@ -31,3 +34,82 @@ func getStdinReader(defaultInput fs.File) (stdinInput, error) {
 		IsPipe: stat.Mode()&os.ModeNamedPipe != 0,
 	}, nil
 }
+
+// sampleInput reads up to maxLines first lines of the input that arrive
+// before the timeout. The returned reader yields the whole input, including
+// the sample.
+func sampleInput(input io.Reader, maxLines int, timeout time.Duration) ([]byte, io.Reader) {
+	lines := make(chan []byte, maxLines)
+	pipeReader, pipeWriter := io.Pipe()
+
+	go func() {
+		reader := bufio.NewReader(input)
+
+		for range maxLines {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				lines <- line
+			}
+
+			if err != nil {
+				close(lines)
+
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+
+				pipeWriter.CloseWithError(err)
+
+				return
+			}
+		}
+
+		close(lines)
+
+		// The rest of the input follows the lines of the sample.
+		_, err := reader.WriteTo(pipeWriter)
+		pipeWriter.CloseWithError(err)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var sample []byte
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return sample, io.MultiReader(bytes.NewReader(sample), pipeReader)
+			}
+
+			sample = append(sample, line...)
+		case <-timer.C:
+			// Lines that arrive later are read from the channel.
+			return sample, io.MultiReader(bytes.NewReader(sample), &linesReader{lines: lines}, pipeReader)
+		}
+	}
+}
+
+// linesReader reads lines from the channel until it is closed.
+type linesReader struct {
+	lines   <-chan []byte
+	current []byte
+}
+
+// Read implements io.Reader.
+func (r *linesReader) Read(p []byte) (int, error) {
+	for len(r.current) == 0 {
+		line, ok := <-r.lines
+		if !ok {
+			return 0, io.EOF
+		}
+
+		r.current = line
+	}
+
+	n := copy(p, r.current)
+	r.current = r.current[n:]
+
+	return n, nil
+}
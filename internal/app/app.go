@@ -9,7 +9,9 @@ import (
 	"github.com/hedhyw/bubbles/table"
 
 	"github.com/hedhyw/json-log-viewer/internal/keymap"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/clipboard"
 	"github.com/hedhyw/json-log-viewer/internal/pkg/events"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/session"
 	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
 
 	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
@@ -20,10 +22,30 @@ type Application struct {
 	lock *sync.Mutex
 
 	FileName string
-	Config   *config.Config
+	// Config is the active config. It differs from the base config if a view
+	// with its own columns is opened.
+	Config     *config.Config
+	baseConfig *config.Config
+	// viewName is the name of the opened view, it is empty by default.
+	viewName string
+	// preview shows the JSON of the selected entry next to the table.
+	preview bool
+	// timeMode is the way times are shown in tables. relativeTimeTicks
+	// counts started tickers of relative times to keep only the last one.
+	timeMode          timeMode
+	relativeTimeTicks int
+	// sort orders entries of tables, they are in the order of the log if it
+	// is zero. sortRequests counts sortings to identify their results.
+	sort         config.Sort
+	sortRequests int
 
 	BaseStyle   lipgloss.Style
 	FooterStyle lipgloss.Style
+	theme       theme
+	highlighter source.Highlighter
+	// configErr is an error of the config that is found when the
+	// application is created, it is shown on start.
+	configErr error
 
 	lastWindowSize tea.WindowSizeMsg
 	entries        source.LazyLogEntries
@@ -31,26 +53,56 @@ type Application struct {
 
 	keys keymap.KeyMap
 	help help.Model
+
+	clipboard clipboard.Clipboard
+
+	marks marks
+	// notes are annotations of entries by their index in the whole log.
+	notes map[int]string
+
+	// restore is the previous session, it is nil after it is restored.
+	restore *session.State
+	// view is the position and the filter of the table for the session.
+	view session.State
+}
+
+// Option customizes the application.
+type Option func(*Application)
+
+// WithClipboard sets the clipboard that receives copied values.
+func WithClipboard(c clipboard.Clipboard) Option {
+	return func(app *Application) {
+		app.clipboard = c
+	}
 }
 
 func newApplication(
 	fileName string,
 	config *config.Config,
 	version string,
+	options ...Option,
 ) Application {
 	const (
 		initialWidth  = 70
 		initialHeight = 20
 	)
 
-	return Application{
+	theme := getTheme(config)
+
+	highlighter, configErr := source.NewHighlighter(config.Highlights, config)
+
+	application := Application{
 		lock: &sync.Mutex{},
 
-		FileName: fileName,
-		Config:   config,
+		FileName:   fileName,
+		Config:     config,
+		baseConfig: config,
 
-		BaseStyle:   getBaseStyle(),
+		BaseStyle:   theme.baseStyle(),
 		FooterStyle: getFooterStyle(),
+		theme:       theme,
+		highlighter: highlighter,
+		configErr:   configErr,
 
 		lastWindowSize: tea.WindowSizeMsg{
 			Width:  initialWidth,
@@ -58,9 +110,36 @@ func newApplication(
 		},
 
 		Version: version,
-		keys:    keymap.GetDefaultKeys(),
+		keys:    getKeys(config),
 		help:    help.New(),
+
+		clipboard: clipboard.New(),
+
+		marks: marks{},
+		notes: map[int]string{},
+
+		view: session.State{
+			Cursor:  -1,
+			Reverse: config.IsReverseDefault,
+		},
+	}
+
+	for _, option := range options {
+		option(&application)
 	}
+
+	return application
+}
+
+// getKeys returns keys of the config. Keys are validated when the config is
+// read, the default keys are used if they are still invalid.
+func getKeys(cfg *config.Config) keymap.KeyMap {
+	keys, err := keymap.New(cfg.Keys)
+	if err != nil {
+		return keymap.GetDefaultKeys()
+	}
+
+	return keys
 }
 
 // NewModel initializes a new application model. It accept the path
@@ -69,8 +148,9 @@ func NewModel(
 	fileName string,
 	config *config.Config,
 	version string,
+	options ...Option,
 ) tea.Model {
-	application := newApplication(fileName, config, version)
+	application := newApplication(fileName, config, version, options...)
 
 	return newStateInitial(&application)
 }
@@ -86,7 +166,7 @@ func (app *Application) getLogLevelStyle(
 
 	row := renderedRows[rowID]
 
-	color := getColorForLogLevel(app.getLogLevelFromLogRow(row))
+	color := app.theme.levelColor(app.getLogLevelFromLogRow(row))
 	if color == "" {
 		return baseStyle
 	}
@@ -28,6 +28,17 @@ func newTestModel(
 ) tea.Model {
 	tb.Helper()
 
+	return newTestModelWithOptions(tb, content, nil, configSetters...)
+}
+
+func newTestModelWithOptions(
+	tb testing.TB,
+	content []byte,
+	options []app.Option,
+	configSetters ...configSetter,
+) tea.Model {
+	tb.Helper()
+
 	testFile := tests.RequireCreateFile(tb, content)
 
 	cfg := config.GetDefaultConfig()
@@ -38,7 +49,7 @@ func newTestModel(
 
 	inputSource, err := source.File(testFile, cfg)
 	require.NoError(tb, err)
-	model := app.NewModel(testFile, cfg, testVersion)
+	model := app.NewModel(testFile, cfg, testVersion, options...)
 
 	entries, err := inputSource.ParseLogEntries()
 	require.NoError(tb, err)
@@ -75,6 +86,11 @@ func handleUpdate(model tea.Model, msg tea.Msg) tea.Model {
 			break
 		}
 
+		// Relative times are refreshed forever.
+		if _, ok := msg.(app.RelativeTimeTickMsg); ok {
+			continue
+		}
+
 		if batch, ok := msg.(tea.BatchMsg); ok {
 			cmdsBatch = append(cmdsBatch, batch...)
 
@@ -5,7 +5,6 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/hedhyw/bubbles/key"
 	"github.com/hedhyw/bubbles/table"
 
@@ -14,25 +13,6 @@ import (
 	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
 )
 
-func getColorForLogLevel(level source.Level) lipgloss.Color {
-	switch level {
-	case source.LevelTrace:
-		return colorMagenta
-	case source.LevelDebug:
-		return colorYellow
-	case source.LevelInfo:
-		return colorGreen
-	case source.LevelWarning:
-		return colorOrange
-	case source.LevelError,
-		source.LevelFatal,
-		source.LevelPanic:
-		return colorRed
-	default:
-		return ""
-	}
-}
-
 func (app *Application) getLogLevelFromLogRow(row table.Row) source.Level {
 	return source.Level(getCellByKind(app.Config, config.FieldKindLevel, row))
 }
@@ -44,10 +24,13 @@ func (app *Application) handleErrorOccuredMsg(msg events.ErrorOccuredMsg) (tea.M
 func (app *Application) handleInitialLogEntriesLoadedMsg(
 	msg events.LogEntriesUpdateMsg,
 ) (tea.Model, tea.Cmd) {
-	return initializeModel(newStateViewLogs(
-		app,
-		source.LazyLogEntries(msg),
-	))
+	state := newStateViewLogs(app, source.LazyLogEntries(msg))
+
+	if app.restore != nil && msg.Loaded {
+		return state.restoreSession(state.Init())
+	}
+
+	return initializeModel(state)
 }
 
 func (app *Application) handleOpenJSONRowRequestedMsg(
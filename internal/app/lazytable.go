@@ -2,8 +2,10 @@ package app
 
 import (
 	"slices"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/hedhyw/bubbles/key"
 	"github.com/hedhyw/bubbles/table"
 
@@ -33,7 +35,10 @@ type lazyTableModel struct {
 	reverse    bool
 	follow     bool
 
-	renderedRows []table.Row
+	renderedRows    []table.Row
+	renderedEntries []source.LogEntry
+	// renderedHighlights are highlight rules that match rendered entries.
+	renderedHighlights [][]config.Highlight
 }
 
 type EntriesUpdateMsg struct {
@@ -77,23 +82,89 @@ func (m lazyTableModel) Update(msg tea.Msg) (lazyTableModel, tea.Cmd) {
 
 func (m lazyTableModel) getCellRenderer() func(table.Model, string, table.CellPosition) string {
 	cellIDLogLevel := getIndexByKind(m.Config, config.FieldKindLevel)
-	tableStyles := getTableStyles()
+	tableStyles := m.theme.tableStyles()
 
 	return func(_ table.Model, value string, position table.CellPosition) string {
 		style := tableStyles.Cell
 
-		if position.Column == cellIDLogLevel {
-			return removeClearSequence(
-				m.getLogLevelStyle(
-					m.renderedRows,
-					style,
-					position.RowID,
-				).Render(value),
-			)
+		if position.Column == 0 && m.isMarkedRow(position.RowID) {
+			// The mark replaces the left padding of the first cell.
+			return markGutter + m.renderCell(style.PaddingLeft(0), value, position, cellIDLogLevel)
 		}
 
+		return m.renderCell(style, value, position, cellIDLogLevel)
+	}
+}
+
+func (m lazyTableModel) renderCell(
+	style lipgloss.Style,
+	value string,
+	position table.CellPosition,
+	cellIDLogLevel int,
+) string {
+	highlights := m.cellHighlights(position)
+
+	if position.Column < len(m.Config.Fields) && m.Config.Fields[position.Column].IsNumeric() {
+		value = alignRight(value)
+	}
+
+	if position.Column == cellIDLogLevel {
+		style = m.getLogLevelStyle(
+			m.renderedRows,
+			style,
+			position.RowID,
+		)
+	} else if len(highlights) == 0 {
+		return style.Render(value)
+	}
+
+	keepSelection := true
+
+	for _, highlight := range highlights {
+		style = applyHighlightStyle(style, highlight.Style)
+		keepSelection = keepSelection && highlight.Style.Background == "" && !highlight.Style.Bold
+	}
+
+	if !keepSelection {
+		// The background of the highlight replaces the background of the
+		// selected row.
 		return style.Render(value)
 	}
+
+	return removeClearSequence(style.Render(value))
+}
+
+// cellHighlights returns highlight rules that style the cell.
+func (m lazyTableModel) cellHighlights(position table.CellPosition) []config.Highlight {
+	if position.RowID < 0 || position.RowID >= len(m.renderedHighlights) {
+		return nil
+	}
+
+	var highlights []config.Highlight
+
+	for _, highlight := range m.renderedHighlights[position.RowID] {
+		if highlight.Column == "" || (position.Column < len(m.Config.Fields) &&
+			strings.EqualFold(highlight.Column, m.Config.Fields[position.Column].Title)) {
+			highlights = append(highlights, highlight)
+		}
+	}
+
+	return highlights
+}
+
+// alignRight moves the padding of the cell to the left.
+func alignRight(value string) string {
+	trimmed := strings.TrimRight(value, " ")
+
+	return strings.Repeat(" ", len(value)-len(trimmed)) + trimmed
+}
+
+func (m lazyTableModel) isMarkedRow(rowID int) bool {
+	if rowID < 0 || rowID >= len(m.renderedEntries) {
+		return false
+	}
+
+	return m.marks.has(m.renderedEntries[rowID].Index)
 }
 
 func (m lazyTableModel) handleKey(msg tea.KeyMsg, render bool) (lazyTableModel, bool, bool) {
@@ -239,15 +310,27 @@ func (m lazyTableModel) RenderedRows() lazyTableModel {
 	end := min(m.offset+m.table.Height(), m.entries.Len())
 
 	m.renderedRows = m.renderedRows[:0]
-	renderedEntries := make([]source.LogEntry, 0, cap(m.renderedRows))
+	m.renderedEntries = make([]source.LogEntry, 0, cap(m.renderedRows))
+	m.renderedHighlights = make([][]config.Highlight, 0, cap(m.renderedRows))
+	var previous *source.LogEntry
+
+	if m.timeMode == timeModeDelta && m.offset > 0 && m.offset <= m.entries.Len() {
+		entry := m.entries.LogEntry(m.Config, m.offset-1)
+		previous = &entry
+	}
+
 	for i := m.offset; i < end; i++ {
-		m.renderedRows = append(m.renderedRows, m.entries.Row(m.Config, i))
-		renderedEntries = append(renderedEntries, m.entries.LogEntry(m.Config, i))
+		entry := m.entries.LogEntry(m.Config, i)
+		m.renderedRows = append(m.renderedRows, m.timeRow(m.annotatedRow(entry), entry, previous))
+		previous = &entry
+		m.renderedEntries = append(m.renderedEntries, entry)
+		m.renderedHighlights = append(m.renderedHighlights, m.highlighter.Match(entry))
 	}
 
 	if m.reverse {
 		slices.Reverse(m.renderedRows)
-		slices.Reverse(renderedEntries)
+		slices.Reverse(m.renderedEntries)
+		slices.Reverse(m.renderedHighlights)
 	}
 
 	m.table.SetRows(m.renderedRows)
@@ -261,9 +344,38 @@ func (m lazyTableModel) RenderedRows() lazyTableModel {
 
 	m.lastCursor = m.table.Cursor()
 
-	tableStyles := getTableStyles()
+	tableStyles := m.theme.tableStyles()
 	tableStyles.RenderCell = m.getCellRenderer()
 	m.table.SetStyles(tableStyles)
 
 	return m
 }
+
+// RenderedTimes renders times of the visible rows again. Entries are not
+// read again and their highlights are kept, so it is cheap to call it for
+// every tick of relative times.
+func (m lazyTableModel) RenderedTimes() lazyTableModel {
+	for i, entry := range m.renderedEntries {
+		m.renderedRows[i] = m.timeRow(m.annotatedRow(entry), entry, nil)
+	}
+
+	m.table.SetRows(m.renderedRows)
+
+	return m
+}
+
+// annotatedRow appends the note of the entry to the last column.
+func (m lazyTableModel) annotatedRow(entry source.LogEntry) table.Row {
+	row := entry.Row()
+
+	note, ok := m.notes[entry.Index]
+	if !ok || len(row) == 0 {
+		return row
+	}
+
+	row = slices.Clone(row)
+	last := len(row) - 1
+	row[last] = strings.TrimRight(row[last], "\r\n") + notePrefix + note
+
+	return row
+}
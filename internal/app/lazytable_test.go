@@ -7,6 +7,8 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
 )
 
 func TestLazyTableModelManyRows(t *testing.T) {
@@ -157,3 +159,18 @@ func TestLazyTableModelReverse(t *testing.T) {
 		assert.Less(t, strings.Index(view, start), (len(view) / 2), view)
 	})
 }
+
+func TestLazyTableModelAlignNumbers(t *testing.T) {
+	t.Parallel()
+
+	const content = `{"message":"request","size":1536}` + "\n"
+
+	model := newTestModel(t, []byte(content), func(cfg *config.Config) {
+		cfg.Fields = []config.Field{
+			{Title: "Size", Kind: config.FieldKindBytes, References: []string{"$.size"}, Width: 10},
+			{Title: "Message", Kind: config.FieldKindMessage, References: []string{"$.message"}, Width: 10},
+		}
+	})
+
+	assert.Contains(t, model.View(), "    1.5KiB  request")
+}
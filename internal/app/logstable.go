@@ -2,6 +2,7 @@ package app
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hedhyw/bubbles/key"
 	"github.com/hedhyw/bubbles/table"
 
 	"github.com/hedhyw/json-log-viewer/internal/pkg/events"
@@ -15,8 +16,15 @@ type logsTableModel struct {
 	lazyTable      lazyTableModel
 	lastWindowSize tea.WindowSizeMsg
 	footerSize     int
+	previewPane    previewPane
 
 	logEntries source.LazyLogEntries
+	// unsortedEntries are entries in the order of the log.
+	unsortedEntries source.LazyLogEntries
+	// sorted is the order of the shown entries, sortID identifies the last
+	// requested sorting.
+	sorted sortRequest
+	sortID int
 }
 
 func newLogsTableModel(
@@ -37,7 +45,7 @@ func newLogsTableModel(
 	tableLogs.KeyMap.GotoBottom = application.keys.GotoBottom
 	tableLogs.KeyMap.GotoTop = application.keys.GotoTop
 
-	tableLogs.SetStyles(getTableStyles())
+	tableLogs.SetStyles(application.theme.tableStyles())
 
 	lazyTable := lazyTableModel{
 		Application:  application,
@@ -54,6 +62,8 @@ func newLogsTableModel(
 		lazyTable:   lazyTable,
 		logEntries:  logEntries,
 		footerSize:  1,
+
+		unsortedEntries: logEntries,
 	}.handleWindowSizeMsg(application.LastWindowSize())
 
 	return msg
@@ -61,7 +71,17 @@ func newLogsTableModel(
 
 // View renders component. It implements tea.Model.
 func (m logsTableModel) View() string {
-	return m.lazyTable.View()
+	if m.previewPane == (previewPane{}) {
+		return m.lazyTable.View()
+	}
+
+	var content []byte
+
+	if cursor := m.Cursor(); cursor >= 0 && cursor < m.logEntries.Len() {
+		content = m.logEntries.LogEntry(m.Config, cursor).Content()
+	}
+
+	return m.previewPane.render(m.lazyTable.View(), content, m.theme.border)
 }
 
 // Update handles events. It implements tea.Model.
@@ -74,8 +94,24 @@ func (m logsTableModel) Update(msg tea.Msg) (logsTableModel, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m = m.handleWindowSizeMsg(typedMsg)
 	case events.LogEntriesUpdateMsg:
-		m.logEntries = source.LazyLogEntries(typedMsg)
+		m.unsortedEntries = source.LazyLogEntries(typedMsg)
+
+		if m.sort.IsZero() {
+			m.logEntries = m.unsortedEntries
+			m.sorted, m.sortID = sortRequest{}, 0
+		} else {
+			m, cmdBatch = batched(m.requestSort())(cmdBatch)
+		}
+
 		msg = EntriesUpdateMsg{Entries: m.logEntries}
+	case entriesSortedMsg:
+		return m.handleEntriesSortedMsg(typedMsg)
+	case RelativeTimeTickMsg:
+		return m.handleRelativeTimeTickMsg(typedMsg)
+	case tea.KeyMsg:
+		if handled, ok := m.handleMarkKeys(typedMsg); ok {
+			return handled, nil
+		}
 	}
 
 	m.lazyTable, cmdBatch = batched(m.lazyTable.Update(msg))(cmdBatch)
@@ -91,8 +127,19 @@ func (m logsTableModel) handleWindowSizeMsg(msg tea.WindowSizeMsg) logsTableMode
 	)
 
 	x, y := m.BaseStyle.GetFrameSize()
-	m.lazyTable.table.SetWidth(msg.Width - x*2)
-	m.lazyTable.table.SetHeight(max(msg.Height-y-headerSize-m.footerSize, 1))
+	width := msg.Width - x*2
+	height := max(msg.Height-y-m.footerSize, 1)
+
+	m.previewPane = previewPane{}
+	if m.preview {
+		m.previewPane, width, height = getPreviewPane(width, height)
+	}
+
+	m.lazyTable.table.SetWidth(width)
+	m.lazyTable.table.SetHeight(max(height-headerSize, 1))
+	// Rendered rows could have cells of columns of another view. They are
+	// rendered again below.
+	m.lazyTable.table.SetRows(nil)
 	m.lazyTable.table.SetColumns(getColumns(m.lazyTable.table.Width()+widthOffset, m.Config))
 	m.lastWindowSize = msg
 
@@ -116,3 +163,53 @@ func (m logsTableModel) Select(index int) logsTableModel {
 
 	return m
 }
+
+// SelectIndex selects the entry by its index in the whole log.
+func (m logsTableModel) SelectIndex(index int) logsTableModel {
+	for i, entry := range m.logEntries.Entries {
+		if entry.Index() == index {
+			return m.Select(i)
+		}
+	}
+
+	return m
+}
+
+// handleMarkKeys toggles the mark of the selected entry and jumps between
+// marked entries. It returns false if the key is not related to marks.
+func (m logsTableModel) handleMarkKeys(msg tea.KeyMsg) (logsTableModel, bool) {
+	cursor := m.Cursor()
+	if cursor < 0 || cursor >= m.logEntries.Len() {
+		return m, false
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Mark):
+		m.marks.toggle(m.logEntries.Entries[cursor].Index())
+		m.lazyTable = m.lazyTable.RenderedRows()
+
+		return m, true
+	case key.Matches(msg, m.keys.NextMark):
+		return m.selectMark(cursor, 1), true
+	case key.Matches(msg, m.keys.PrevMark):
+		return m.selectMark(cursor, -1), true
+	default:
+		return m, false
+	}
+}
+
+// selectMark selects the closest marked entry in the direction from the
+// cursor. The search wraps around the end of the log.
+func (m logsTableModel) selectMark(cursor int, direction int) logsTableModel {
+	total := m.logEntries.Len()
+
+	for step := 1; step <= total; step++ {
+		i := ((cursor+direction*step)%total + total) % total
+
+		if m.marks.has(m.logEntries.Entries[i].Index()) {
+			return m.Select(i)
+		}
+	}
+
+	return m
+}
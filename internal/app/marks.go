@@ -0,0 +1,20 @@
+package app
+
+// marks are bookmarked entries. They are keyed by the index of the entry in
+// the whole log, so they survive filtering.
+type marks map[int]struct{}
+
+// toggle marks the entry or removes the mark.
+func (m marks) toggle(index int) {
+	if _, ok := m[index]; ok {
+		delete(m, index)
+	} else {
+		m[index] = struct{}{}
+	}
+}
+
+func (m marks) has(index int) bool {
+	_, ok := m[index]
+
+	return ok
+}
@@ -0,0 +1,103 @@
+package app_test
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/app"
+)
+
+func TestMarks(t *testing.T) {
+	t.Parallel()
+
+	const jsonFile = `{"time":"1970-01-01T00:00:00.00","level":"INFO","message":"first"}
+{"time":"1970-01-01T00:00:01.00","level":"INFO","message":"second"}
+{"time":"1970-01-01T00:00:02.00","level":"INFO","message":"third"}
+`
+
+	pressKey := func(model tea.Model, key rune) tea.Model {
+		return handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}})
+	}
+
+	setup := func(t *testing.T) tea.Model {
+		t.Helper()
+
+		model := newTestModel(t, []byte(jsonFile))
+
+		_, ok := model.(app.StateLoadedModel)
+		require.Truef(t, ok, "%s", model)
+
+		assert.NotContains(t, model.View(), "*")
+
+		return pressKey(model, 'm')
+	}
+
+	t.Run("gutter", func(t *testing.T) {
+		t.Parallel()
+
+		model := setup(t)
+		assert.Contains(t, model.View(), "*")
+
+		model = pressKey(model, 'm')
+		assert.NotContains(t, model.View(), "*")
+	})
+
+	t.Run("marked_only", func(t *testing.T) {
+		t.Parallel()
+
+		model := setup(t)
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyDown})
+		model = pressKey(model, 'm')
+		model = pressKey(model, 'M')
+
+		_, ok := model.(app.StateFilteredModel)
+		require.Truef(t, ok, "%s", model)
+
+		view := model.View()
+		assert.Contains(t, view, "marked 2")
+		assert.Contains(t, view, "third")
+		assert.Contains(t, view, "second")
+		assert.NotContains(t, view, "first")
+
+		model = pressKey(model, 'M')
+		assert.Contains(t, model.View(), "first")
+
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEsc})
+		_, ok = model.(app.StateLoadedModel)
+		assert.Truef(t, ok, "%s", model)
+	})
+
+	t.Run("survive_filtering", func(t *testing.T) {
+		t.Parallel()
+
+		model := setup(t)
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("third")})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+
+		_, ok := model.(app.StateFilteredModel)
+		require.Truef(t, ok, "%s", model)
+		assert.Contains(t, model.View(), "*")
+
+		model = pressKey(model, 'M')
+		assert.Contains(t, model.View(), "marked only")
+		assert.Contains(t, model.View(), "third")
+	})
+
+	for _, key := range []rune{'[', ']'} {
+		t.Run("jump_"+string(key), func(t *testing.T) {
+			t.Parallel()
+
+			model := setup(t)
+			model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyDown})
+			model = pressKey(model, key)
+
+			// The selected entry is marked, so the mark is removed.
+			model = pressKey(model, 'm')
+			assert.NotContains(t, model.View(), "*")
+		})
+	}
+}
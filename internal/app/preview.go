@@ -0,0 +1,87 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// previewSideMinWidth is the width of the window starting from which the
+// preview is shown to the right of the table instead of below it.
+const previewSideMinWidth = 160
+
+// previewPane is the size of the preview of the selected entry. Zero size
+// means that the preview is hidden.
+type previewPane struct {
+	Width  int
+	Height int
+	// Side is true if the preview is to the right of the table.
+	Side bool
+}
+
+// getPreviewPane splits the space of the table between the table and the
+// preview. It returns the space that is left for the table.
+func getPreviewPane(width int, height int) (pane previewPane, tableWidth int, tableHeight int) {
+	// The border between the table and the preview.
+	const borderSize = 1
+
+	if width >= previewSideMinWidth {
+		tableWidth = width / 2
+
+		return previewPane{
+			Width:  max(width-tableWidth-borderSize, 1),
+			Height: height,
+			Side:   true,
+		}, tableWidth, height
+	}
+
+	tableHeight = max(height/2, 1)
+
+	return previewPane{
+		Width:  width,
+		Height: max(height-tableHeight-borderSize, 1),
+	}, width, tableHeight
+}
+
+// render joins the table with the JSON of the entry, the pane is separated
+// by the border of the color.
+func (p previewPane) render(tableView string, content []byte, border lipgloss.Color) string {
+	lines := strings.Split(prettyJSON(content), "\n")
+	if len(content) == 0 {
+		lines = nil
+	}
+
+	lines = lines[:min(len(lines), p.Height)]
+
+	textWidth := p.Width
+	if p.Side {
+		// The padding after the border.
+		textWidth--
+	}
+
+	for i, line := range lines {
+		line = strings.ReplaceAll(line, "\t", " ")
+
+		if runes := []rune(line); len(runes) > textWidth {
+			line = string(runes[:max(textWidth-1, 0)]) + "…"
+		}
+
+		lines[i] = line
+	}
+
+	style := lipgloss.NewStyle().
+		Width(p.Width).
+		Height(p.Height).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(border)
+
+	if p.Side {
+		style = style.BorderLeft(true).PaddingLeft(1)
+
+		return lipgloss.JoinHorizontal(lipgloss.Top, tableView, style.Render(strings.Join(lines, "\n")))
+	}
+
+	style = style.BorderTop(true)
+
+	return lipgloss.JoinVertical(lipgloss.Left, tableView, style.Render(strings.Join(lines, "\n")))
+}
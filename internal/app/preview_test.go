@@ -0,0 +1,83 @@
+package app_test
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/app"
+)
+
+const previewTestLog = `{"time":"1970-01-01T00:00:00.00","level":"INFO","message":"first","request":{"id":"r-1"}}
+{"time":"1970-01-01T00:00:01.00","level":"ERROR","message":"second","request":{"id":"r-2"}}
+`
+
+func TestPreview(t *testing.T) {
+	t.Parallel()
+
+	togglePreview := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}}
+
+	testCases := [...]struct {
+		Name       string
+		WindowSize tea.WindowSizeMsg
+	}{{
+		Name:       "below",
+		WindowSize: tea.WindowSizeMsg{Width: 80, Height: 30},
+	}, {
+		Name:       "side",
+		WindowSize: tea.WindowSizeMsg{Width: 180, Height: 30},
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			model := newTestModel(t, []byte(previewTestLog))
+			model = handleUpdate(model, testCase.WindowSize)
+			assert.NotContains(t, model.View(), `"r-2"`)
+
+			model = handleUpdate(model, togglePreview)
+
+			_, ok := model.(app.StateLoadedModel)
+			require.Truef(t, ok, "%s", model)
+
+			// The preview follows the cursor.
+			view := model.View()
+			assert.Contains(t, view, `"id": "r-2"`)
+			assert.LessOrEqual(t, lipgloss.Height(view), testCase.WindowSize.Height)
+			assert.LessOrEqual(t, lipgloss.Width(strings.Split(view, "\n")[0]), testCase.WindowSize.Width)
+
+			model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyDown})
+			assert.Contains(t, model.View(), `"id": "r-1"`)
+
+			model = handleUpdate(model, togglePreview)
+			assert.NotContains(t, model.View(), `"r-1"`)
+		})
+	}
+}
+
+func TestPreviewFiltered(t *testing.T) {
+	t.Parallel()
+
+	model := newTestModel(t, []byte(previewTestLog))
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+
+	for _, r := range "second" {
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+
+	_, ok := model.(app.StateFilteredModel)
+	require.Truef(t, ok, "%s", model)
+
+	view := model.View()
+	assert.Contains(t, view, `"id": "r-2"`)
+	assert.NotContains(t, view, "r-1")
+}
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/export"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
+)
+
+// Print writes entries to the writer without starting the interactive
+// program. Levels and highlights of the text format are colorized the same
+// way as in the table, colors are omitted if the writer is not a terminal.
+func Print(
+	w io.Writer,
+	entries source.LazyLogEntries,
+	cfg *config.Config,
+	format export.Format,
+) (err error) {
+	renderer := lipgloss.NewRenderer(w)
+	levelIndex := getIndexByKind(cfg, config.FieldKindLevel)
+	theme := getTheme(cfg)
+
+	highlighter, err := source.NewHighlighter(cfg.Highlights, cfg)
+	if err != nil {
+		return fmt.Errorf("preparing highlights: %w", err)
+	}
+
+	// Cells of the same entry are styled one after another, so its
+	// highlights are matched once.
+	highlightedIndex := -1
+
+	var highlights []config.Highlight
+
+	job := &export.Job{
+		Entries: entries,
+		Config:  cfg,
+		Format:  format,
+		StyleCell: func(entry source.LogEntry, column int, value string) string {
+			if entry.Index != highlightedIndex {
+				highlightedIndex = entry.Index
+				highlights = highlighter.Match(entry)
+			}
+
+			style := renderer.NewStyle()
+			styled := false
+
+			if column == levelIndex {
+				if color := theme.levelColor(source.Level(value)); color != "" {
+					style = style.Foreground(color)
+					styled = true
+				}
+			}
+
+			for _, highlight := range highlights {
+				if highlight.Column == "" || (column < len(cfg.Fields) && strings.EqualFold(highlight.Column, cfg.Fields[column].Title)) {
+					style = applyHighlightStyle(style, highlight.Style)
+					styled = true
+				}
+			}
+
+			if !styled {
+				return value
+			}
+
+			return style.Render(value)
+		},
+	}
+
+	buffered := bufio.NewWriter(w)
+
+	defer func() { err = errors.Join(err, buffered.Flush()) }()
+
+	if err := job.Write(context.Background(), buffered); err != nil {
+		return fmt.Errorf("printing: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/session"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
+)
+
+// WithSession restores filters, marks, notes and the selected entry of the
+// previous session.
+func WithSession(state session.State) Option {
+	return func(app *Application) {
+		app.restore = &state
+
+		for _, index := range state.Marks {
+			app.marks[index] = struct{}{}
+		}
+
+		maps.Copy(app.notes, state.Notes)
+	}
+}
+
+// SessionState returns the state of the application to restore it later.
+func SessionState(model tea.Model) (session.State, bool) {
+	state, ok := model.(stateModel)
+	if !ok {
+		return session.State{}, false
+	}
+
+	app := state.getApplication()
+
+	result := app.view
+	if app.restore != nil {
+		// The session was not restored yet, so only marks and notes are
+		// updated.
+		result = *app.restore
+	}
+
+	result.Marks = slices.Sorted(maps.Keys(app.marks))
+	result.Notes = maps.Clone(app.notes)
+
+	return result, true
+}
+
+// rememberView saves the position of the table, the opened view and the
+// filter for the session.
+func (app *Application) rememberView(table logsTableModel, filter session.State) {
+	filter.Cursor = -1
+
+	if position := table.Cursor(); !table.lazyTable.follow && position >= 0 && position < table.logEntries.Len() {
+		filter.Cursor = table.logEntries.Entries[position].Index()
+	}
+
+	filter.Reverse = table.lazyTable.reverse
+	filter.View = app.viewName
+
+	app.view = filter
+}
+
+// restoreSession selects the entry and applies the filter of the previous
+// session. It is called once the input is loaded. The last entry is
+// selected if the log became shorter.
+func (s StateLoadedModel) restoreSession(cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	restore := *s.restore
+	s.restore = nil
+
+	restore.Cursor = min(restore.Cursor, s.table.logEntries.Len()-1)
+
+	// An unknown view is ignored, because the config could be changed.
+	s.useView(restore.View)
+
+	s.table.lazyTable.reverse = restore.Reverse
+	if restore.Cursor >= 0 {
+		s.table = s.table.SelectIndex(restore.Cursor)
+	}
+
+	s, cmdRefresh := s.refreshTable()
+	s.rememberView(s.table, session.State{})
+
+	if restore.FilterText == "" && !restore.MarkedOnly && restore.MinLevel == "" {
+		return s, tea.Batch(cmd, cmdRefresh)
+	}
+
+	filtered := newStateFiltered(s, restore.FilterText, restore.FilterField, restore.MarkedOnly)
+	filtered.minLevel = source.Level(strings.ToLower(restore.MinLevel))
+	filtered.selectIndex = restore.Cursor
+
+	return filtered, tea.Batch(cmd, cmdRefresh, filtered.Init())
+}
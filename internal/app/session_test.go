@@ -0,0 +1,181 @@
+package app_test
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/app"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/events"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/session"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/tests"
+)
+
+const sessionTestLog = `{"time":"1970-01-01T00:00:00.00","level":"INFO","message":"first"}
+{"time":"1970-01-01T00:00:01.00","level":"ERROR","message":"second"}
+{"time":"1970-01-01T00:00:02.00","level":"INFO","message":"third"}
+`
+
+func TestSessionState(t *testing.T) {
+	t.Parallel()
+
+	model := newTestModel(t, []byte(sessionTestLog))
+
+	state, ok := app.SessionState(model)
+	require.True(t, ok)
+	assert.Equal(t, -1, state.Cursor, "following")
+	assert.True(t, state.Reverse)
+
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyDown})
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ir")})
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("rc")})
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+
+	_, ok = model.(app.StateFilteredModel)
+	require.Truef(t, ok, "%s", model)
+
+	state, ok = app.SessionState(model)
+	require.True(t, ok)
+
+	assert.Equal(t, session.State{
+		FilterText: "ir",
+		Reverse:    true,
+		Cursor:     2,
+		Marks:      []int{1, 2},
+		Notes:      map[int]string{2: "rc"},
+	}, state)
+
+	_, ok = app.SessionState(nil)
+	assert.False(t, ok)
+}
+
+func TestSessionRestore(t *testing.T) {
+	t.Parallel()
+
+	state := session.State{
+		FilterText: "ir",
+		Reverse:    false,
+		Cursor:     2,
+		Marks:      []int{1},
+		Notes:      map[int]string{2: "rc"},
+	}
+
+	model := newTestModelWithOptions(t, []byte(sessionTestLog), []app.Option{app.WithSession(state)})
+
+	_, ok := model.(app.StateFilteredModel)
+	require.Truef(t, ok, "%s", model)
+
+	view := model.View()
+	assert.Contains(t, view, "filtered 2 by: ir")
+	assert.Contains(t, view, "✎ rc")
+	assert.NotContains(t, view, "second")
+
+	actual, ok := app.SessionState(model)
+	require.True(t, ok)
+	assert.Equal(t, state, actual)
+
+	// The restored entry is selected.
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Contains(t, model.View(), "Note: rc")
+	assert.Contains(t, model.View(), "third")
+
+	t.Run("entry_removed", func(t *testing.T) {
+		t.Parallel()
+
+		// The log became shorter, so the last entry is selected.
+		model := newTestModelWithOptions(t, []byte(sessionTestLog), []app.Option{app.WithSession(session.State{
+			FilterText: "ir",
+			Cursor:     10,
+		})})
+
+		_, ok := model.(app.StateFilteredModel)
+		require.Truef(t, ok, "%s", model)
+
+		actual, ok := app.SessionState(model)
+		require.True(t, ok)
+		assert.Equal(t, 2, actual.Cursor)
+	})
+
+	t.Run("not_loaded", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.GetDefaultConfig()
+
+		inputSource, err := source.File(tests.RequireCreateFile(t, []byte(sessionTestLog)), cfg)
+		require.NoError(t, err)
+
+		t.Cleanup(func() { assert.NoError(t, inputSource.Close()) })
+
+		entries, err := inputSource.ParseLogEntries()
+		require.NoError(t, err)
+
+		entries.Loaded = false
+
+		model := handleUpdate(app.NewModel("-", cfg, testVersion, app.WithSession(state)), events.LogEntriesUpdateMsg(entries))
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+
+		_, ok := model.(app.StateLoadedModel)
+		require.Truef(t, ok, "%s", model)
+
+		// The session is kept, but new marks are saved.
+		actual, ok := app.SessionState(model)
+		require.True(t, ok)
+		assert.Equal(t, state.FilterText, actual.FilterText)
+		assert.Equal(t, []int{1, 2}, actual.Marks)
+
+		entries.Loaded = true
+
+		model = handleUpdate(model, events.LogEntriesUpdateMsg(entries))
+
+		_, ok = model.(app.StateFilteredModel)
+		require.Truef(t, ok, "%s", model)
+	})
+}
+
+func TestStateAnnotating(t *testing.T) {
+	t.Parallel()
+
+	model := newTestModel(t, []byte(sessionTestLog))
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+
+	_, ok := model.(app.StateAnnotatingModel)
+	require.Truef(t, ok, "%s", model)
+	assert.Contains(t, model.View(), "Note:")
+
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("qn")})
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+
+	_, ok = model.(app.StateLoadedModel)
+	require.Truef(t, ok, "%s", model)
+	assert.Contains(t, model.View(), "✎ qn")
+	assert.Contains(t, model.View(), "*")
+
+	// The note is edited and removed by an empty value.
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	assert.Contains(t, model.View(), "qn")
+
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyCtrlU})
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.NotContains(t, model.View(), "qn")
+
+	t.Run("canceled", func(t *testing.T) {
+		t.Parallel()
+
+		model := newTestModel(t, []byte(sessionTestLog))
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEsc})
+
+		_, ok := model.(app.StateLoadedModel)
+		require.Truef(t, ok, "%s", model)
+		assert.NotContains(t, model.View(), "✎")
+	})
+}
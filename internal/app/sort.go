@@ -0,0 +1,125 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/events"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
+)
+
+// sortRequest identifies the order of entries of the table. The log only
+// grows, so the number of entries identifies them.
+type sortRequest struct {
+	sort config.Sort
+	len  int
+}
+
+// entriesSortedMsg is sent when entries of the table are sorted in the
+// background.
+type entriesSortedMsg struct {
+	id      int
+	request sortRequest
+	entries source.LazyLogEntries
+}
+
+// requestSort sorts entries in the background, unless they are already
+// sorted. Results of previous requests are ignored.
+func (m logsTableModel) requestSort() (logsTableModel, tea.Cmd) {
+	request := sortRequest{sort: m.sort, len: m.unsortedEntries.Len()}
+	if request == m.sorted {
+		return m, nil
+	}
+
+	m.sortRequests++
+	m.sortID = m.sortRequests
+
+	id := m.sortID
+	entries := m.unsortedEntries
+	cfg := m.Config
+
+	sortEntries := func() (source.LazyLogEntries, error) {
+		return entries.Sort(request.sort, cfg)
+	}
+
+	// Entries that are appended to the followed log are merged into
+	// the shown ones instead of sorting the whole log again.
+	if previous := m.sorted; previous.sort == request.sort && previous.len < request.len {
+		sorted := m.logEntries
+		added := source.LazyLogEntries{
+			Seeker:  entries.Seeker,
+			Entries: entries.Entries[previous.len:],
+		}
+
+		sortEntries = func() (source.LazyLogEntries, error) {
+			return sorted.Merge(added, request.sort, cfg)
+		}
+	}
+
+	return m, func() tea.Msg {
+		sorted, err := sortEntries()
+		if err != nil {
+			return events.ErrorOccuredMsg{Err: err}
+		}
+
+		return entriesSortedMsg{
+			id:      id,
+			request: request,
+			entries: sorted,
+		}
+	}
+}
+
+// refreshSort applies the sort order of the application to the table.
+func (m logsTableModel) refreshSort() (logsTableModel, tea.Cmd) {
+	if !m.sort.IsZero() {
+		return m.requestSort()
+	}
+
+	m.sortID = 0
+
+	if m.sorted.sort.IsZero() {
+		return m, nil
+	}
+
+	m.sorted = sortRequest{}
+
+	return m.showEntries(m.unsortedEntries)
+}
+
+// handleEntriesSortedMsg shows sorted entries if they are sorted by the
+// last request.
+func (m logsTableModel) handleEntriesSortedMsg(msg entriesSortedMsg) (logsTableModel, tea.Cmd) {
+	if msg.id != m.sortID {
+		return m, nil
+	}
+
+	m.sorted = msg.request
+
+	return m.showEntries(msg.entries)
+}
+
+// isSorting returns true while entries are sorted in the background.
+func (m logsTableModel) isSorting() bool {
+	return !m.sort.IsZero() && m.sorted.sort != m.sort
+}
+
+// showEntries replaces entries of the table and keeps the selected entry.
+func (m logsTableModel) showEntries(entries source.LazyLogEntries) (logsTableModel, tea.Cmd) {
+	selected := -1
+
+	if cursor := m.Cursor(); !m.lazyTable.follow && cursor >= 0 && cursor < m.logEntries.Len() {
+		selected = m.logEntries.Entries[cursor].Index()
+	}
+
+	var cmd tea.Cmd
+
+	m.logEntries = entries
+	m.lazyTable, cmd = m.lazyTable.Update(EntriesUpdateMsg{Entries: entries})
+
+	if selected >= 0 {
+		m = m.SelectIndex(selected)
+	}
+
+	return m, cmd
+}
@@ -13,3 +13,11 @@ type stateModel interface {
 	getApplication() *Application
 	refresh() (stateModel, tea.Cmd)
 }
+
+// tableStateModel is a state that shows entries in the table.
+type tableStateModel interface {
+	stateModel
+
+	getTable() logsTableModel
+	withTable(table logsTableModel) stateModel
+}
@@ -0,0 +1,141 @@
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hedhyw/bubbles/key"
+	"github.com/hedhyw/bubbles/textinput"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/events"
+)
+
+// StateAnnotatingModel is a state that prompts for a note of the selected
+// entry.
+type StateAnnotatingModel struct {
+	*Application
+
+	previousState stateModel
+	table         logsTableModel
+
+	// index of the annotated entry in the whole log.
+	index int
+
+	textInput textinput.Model
+}
+
+func newStateAnnotating(
+	previousState stateModel,
+	table logsTableModel,
+) StateAnnotatingModel {
+	app := previousState.getApplication()
+
+	index := -1
+	if cursor := table.Cursor(); cursor >= 0 && cursor < table.logEntries.Len() {
+		index = table.logEntries.Entries[cursor].Index()
+	}
+
+	textInput := textinput.New()
+	textInput.Prompt = "Note: "
+	textInput.Placeholder = "empty to remove"
+	textInput.SetValue(app.notes[index])
+	textInput.Focus()
+
+	return StateAnnotatingModel{
+		Application: app,
+
+		previousState: previousState,
+		table:         table,
+
+		index: index,
+
+		textInput: textInput,
+	}
+}
+
+// Init initializes component. It implements tea.Model.
+func (s StateAnnotatingModel) Init() tea.Cmd {
+	if s.index < 0 {
+		return events.EscKeyClicked
+	}
+
+	return nil
+}
+
+// View renders component. It implements tea.Model.
+func (s StateAnnotatingModel) View() string {
+	return s.BaseStyle.Render(s.table.View()) + "\n" + s.textInput.View()
+}
+
+// Update handles events. It implements tea.Model.
+func (s StateAnnotatingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmdBatch []tea.Cmd
+
+	s.Application.Update(msg)
+
+	switch msg := msg.(type) {
+	case events.ErrorOccuredMsg:
+		return s.handleErrorOccuredMsg(msg)
+	case tea.KeyMsg:
+		return s.handleKeyMsg(msg)
+	case events.LogEntriesUpdateMsg:
+		return s, nil
+	default:
+		s.table, cmdBatch = batched(s.table.Update(msg))(cmdBatch)
+	}
+
+	var cmd tea.Cmd
+
+	s.textInput, cmd = s.textInput.Update(msg)
+	cmdBatch = appendCmd(cmdBatch, cmd)
+
+	return s, tea.Batch(cmdBatch...)
+}
+
+func (s StateAnnotatingModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, s.keys.Exit):
+		return s, tea.Quit
+	case key.Matches(msg, s.keys.Back) && msg.Type != tea.KeyRunes:
+		return s.previousState.refresh()
+	case key.Matches(msg, s.keys.Open):
+		return s.handleEnterKeyClickedMsg()
+	default:
+		var cmd tea.Cmd
+
+		s.textInput, cmd = s.textInput.Update(msg)
+
+		return s, cmd
+	}
+}
+
+// handleEnterKeyClickedMsg saves the note. An entry with a note is marked,
+// so all annotated entries can be listed and exported together. An empty
+// note is removed.
+func (s StateAnnotatingModel) handleEnterKeyClickedMsg() (tea.Model, tea.Cmd) {
+	note := strings.TrimSpace(s.textInput.Value())
+
+	if note == "" {
+		delete(s.notes, s.index)
+	} else {
+		s.notes[s.index] = note
+		s.marks[s.index] = struct{}{}
+	}
+
+	return s.previousState.refresh()
+}
+
+func (s StateAnnotatingModel) getApplication() *Application {
+	return s.Application
+}
+
+func (s StateAnnotatingModel) refresh() (_ stateModel, cmd tea.Cmd) {
+	s.table, cmd = s.table.Update(s.LastWindowSize())
+
+	return s, cmd
+}
+
+// String implements fmt.Stringer.
+func (s StateAnnotatingModel) String() string {
+	return modelValue(s)
+}
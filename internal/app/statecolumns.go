@@ -0,0 +1,374 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hedhyw/bubbles/key"
+	"github.com/hedhyw/bubbles/textinput"
+	"github.com/yalp/jsonpath"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/events"
+)
+
+const (
+	// columnWidthStep is the change of the width of a column per key press.
+	columnWidthStep = 2
+	// maxColumnTitleLength is the longest title that is allowed by the config.
+	maxColumnTitleLength = 32
+)
+
+// StateColumnsModel is a state that adds, hides, reorders and resizes
+// columns of the table. Changes are applied immediately.
+type StateColumnsModel struct {
+	*Application
+
+	previousState stateModel
+
+	fields []config.Field
+	cursor int
+
+	// adding is true while the path of a new column is typed.
+	adding    bool
+	textInput textinput.Model
+
+	// status is a result of the last action.
+	status string
+}
+
+func newStateColumns(previousState stateModel) StateColumnsModel {
+	app := previousState.getApplication()
+
+	textInput := textinput.New()
+	textInput.Prompt = "JSONPath: "
+	textInput.Placeholder = "$.user.id, tab completes observed keys"
+	textInput.ShowSuggestions = true
+	textInput.SetSuggestions(app.Entries().ObservedKeys(app.Config))
+
+	return StateColumnsModel{
+		Application: app,
+
+		previousState: previousState,
+
+		fields: app.Config.Fields,
+
+		textInput: textInput,
+	}
+}
+
+// Init initializes component. It implements tea.Model.
+func (s StateColumnsModel) Init() tea.Cmd {
+	return nil
+}
+
+// View renders component. It implements tea.Model.
+func (s StateColumnsModel) View() string {
+	titleWidth := 0
+	for _, f := range s.fields {
+		titleWidth = max(titleWidth, len([]rune(f.Title)))
+	}
+
+	windowSize := s.LastWindowSize()
+	x, y := s.BaseStyle.GetFrameSize()
+	lineWidth := max(windowSize.Width-x, 1)
+
+	var list strings.Builder
+
+	for i, f := range s.fields {
+		prefix := "  "
+		if i == s.cursor {
+			prefix = "> "
+		}
+
+		width := "auto"
+		if f.Width > 0 {
+			width = strconv.Itoa(f.Width)
+		}
+
+		refs := strings.Join(f.References, ", ")
+		if f.Template != "" {
+			refs = f.Template
+		}
+
+		line := fmt.Sprintf("%s%-*s  %-11s  %4s  %s",
+			prefix, titleWidth, f.Title, f.Kind, width, refs,
+		)
+
+		if runes := []rune(line); len(runes) > lineWidth {
+			line = string(runes[:lineWidth-1]) + "…"
+		}
+
+		list.WriteString(line)
+
+		if i < len(s.fields)-1 {
+			list.WriteString("\n")
+		}
+	}
+
+	return s.BaseStyle.
+		Width(lineWidth).
+		Height(max(windowSize.Height-y-footerSize, 1)).
+		Render(list.String()) + "\n" + s.viewFooter()
+}
+
+func (s StateColumnsModel) viewFooter() string {
+	switch {
+	case s.adding && s.status != "":
+		return s.textInput.View() + s.FooterStyle.Render(s.status)
+	case s.adding:
+		return s.textInput.View()
+	case s.status != "":
+		return s.FooterStyle.Render(s.status)
+	default:
+		help := make([]string, 0, 8)
+
+		for _, binding := range []key.Binding{
+			s.keys.ColumnUp, s.keys.ColumnDown,
+			s.keys.ColumnWider, s.keys.ColumnNarrower, s.keys.ColumnAutoWidth,
+			s.keys.ColumnAdd, s.keys.ColumnHide, s.keys.ColumnSave,
+		} {
+			if !binding.Enabled() {
+				continue
+			}
+
+			help = append(help, binding.Help().Key+" "+binding.Help().Desc)
+		}
+
+		return s.FooterStyle.Render(strings.Join(help, ", "))
+	}
+}
+
+// Update handles events. It implements tea.Model.
+func (s StateColumnsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	s.Application.Update(msg)
+
+	switch msg := msg.(type) {
+	case events.ErrorOccuredMsg:
+		return s.handleErrorOccuredMsg(msg)
+	case tea.KeyMsg:
+		if key.Matches(msg, s.keys.Exit) {
+			return s, tea.Quit
+		}
+
+		if s.adding {
+			return s.handleAddingKeyMsg(msg)
+		}
+
+		return s.handleKeyMsg(msg)
+	}
+
+	if s.adding {
+		var cmd tea.Cmd
+
+		s.textInput, cmd = s.textInput.Update(msg)
+
+		return s, cmd
+	}
+
+	return s, nil
+}
+
+// nolint: cyclop // Switch-case.
+func (s StateColumnsModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s.status = ""
+
+	switch {
+	case key.Matches(msg, s.keys.Back), key.Matches(msg, s.keys.Open):
+		return s.previousState.refresh()
+	case key.Matches(msg, s.keys.ColumnUp):
+		if s.cursor > 0 {
+			s.swapFields(s.cursor, s.cursor-1)
+			s.cursor--
+		}
+	case key.Matches(msg, s.keys.ColumnDown):
+		if s.cursor < len(s.fields)-1 {
+			s.swapFields(s.cursor, s.cursor+1)
+			s.cursor++
+		}
+	case key.Matches(msg, s.keys.Up):
+		s.cursor = max(s.cursor-1, 0)
+	case key.Matches(msg, s.keys.Down):
+		s.cursor = min(s.cursor+1, len(s.fields)-1)
+	case key.Matches(msg, s.keys.ColumnWider):
+		s.resizeField(columnWidthStep)
+	case key.Matches(msg, s.keys.ColumnNarrower):
+		s.resizeField(-columnWidthStep)
+	case key.Matches(msg, s.keys.ColumnAutoWidth):
+		s.updateField(func(f *config.Field) { f.Width = 0 })
+	case key.Matches(msg, s.keys.ColumnHide):
+		return s.hideField(), nil
+	case key.Matches(msg, s.keys.ColumnAdd):
+		s.adding = true
+		s.textInput.SetValue("")
+
+		return s, s.textInput.Focus()
+	case key.Matches(msg, s.keys.ColumnSave):
+		return s.save(), nil
+	}
+
+	return s, nil
+}
+
+func (s StateColumnsModel) handleAddingKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, s.keys.Back) && msg.Type != tea.KeyRunes:
+		s.adding = false
+		s.textInput.Blur()
+
+		return s, nil
+	case key.Matches(msg, s.keys.Open):
+		return s.addField(), nil
+	default:
+		var cmd tea.Cmd
+
+		s.status = ""
+		s.textInput, cmd = s.textInput.Update(msg)
+
+		return s, cmd
+	}
+}
+
+// addField adds a column after the selected one.
+func (s StateColumnsModel) addField() StateColumnsModel {
+	path := strings.TrimSpace(s.textInput.Value())
+	if path == "" {
+		return s
+	}
+
+	if !strings.HasPrefix(path, "$") {
+		path = "$." + path
+	}
+
+	if _, err := jsonpath.Prepare(path); err != nil {
+		s.status = "Invalid path: " + err.Error()
+
+		return s
+	}
+
+	s.adding = false
+	s.textInput.Blur()
+
+	fields := make([]config.Field, 0, len(s.fields)+1)
+	fields = append(fields, s.fields[:s.cursor+1]...)
+	fields = append(fields, config.Field{
+		Title:      columnTitle(path),
+		Kind:       config.FieldKindAny,
+		References: []string{path},
+	})
+	fields = append(fields, s.fields[s.cursor+1:]...)
+
+	s.cursor++
+	s.setFields(fields)
+
+	return s
+}
+
+// columnTitle returns the last key of the path, like "id" for "$.user.id".
+func columnTitle(path string) string {
+	title := strings.TrimPrefix(path, "$")
+
+	if i := strings.LastIndexAny(title, ".["); i >= 0 {
+		title = title[i+1:]
+	}
+
+	title = strings.Trim(title, `"']`)
+	if title == "" {
+		title = path
+	}
+
+	runes := []rune(title)
+
+	return string(runes[:min(len(runes), maxColumnTitleLength)])
+}
+
+func (s StateColumnsModel) hideField() StateColumnsModel {
+	if len(s.fields) <= 1 {
+		s.status = "The last column can't be hidden"
+
+		return s
+	}
+
+	fields := make([]config.Field, 0, len(s.fields)-1)
+	fields = append(fields, s.fields[:s.cursor]...)
+	fields = append(fields, s.fields[s.cursor+1:]...)
+
+	s.cursor = min(s.cursor, len(fields)-1)
+	s.setFields(fields)
+
+	return s
+}
+
+// resizeField changes the width of the selected column. An automatic width
+// is changed starting from the width that it currently has in the table.
+func (s *StateColumnsModel) resizeField(delta int) {
+	width := s.fields[s.cursor].Width
+	if width == 0 {
+		width = getColumns(s.LastWindowSize().Width, s.Config)[s.cursor].Width
+	}
+
+	width = min(max(width+delta, 1), max(s.LastWindowSize().Width, 1))
+
+	s.updateField(func(f *config.Field) { f.Width = width })
+}
+
+func (s *StateColumnsModel) updateField(update func(f *config.Field)) {
+	fields := append([]config.Field(nil), s.fields...)
+	update(&fields[s.cursor])
+
+	s.setFields(fields)
+}
+
+func (s *StateColumnsModel) swapFields(i, j int) {
+	fields := append([]config.Field(nil), s.fields...)
+	fields[i], fields[j] = fields[j], fields[i]
+
+	s.setFields(fields)
+}
+
+// setFields applies the columns to the table.
+func (s *StateColumnsModel) setFields(fields []config.Field) {
+	s.fields = fields
+	s.useFields(fields)
+}
+
+// save writes the columns to the config file that they come from. Columns
+// of a view or a profile are saved to it.
+func (s StateColumnsModel) save() StateColumnsModel {
+	path := s.Config.Path
+	if path == config.PathDefault {
+		path = config.FileName
+	}
+
+	scope := config.Scope{View: s.viewName}
+	if scope.View == "" {
+		scope.Profile = s.Config.Profile
+	}
+
+	if err := config.SaveFields(path, scope, s.fields); err != nil {
+		s.status = "Saving failed: " + err.Error()
+
+		return s
+	}
+
+	s.Config.Path = path
+	s.baseConfig.Path = path
+	s.status = "Saved to " + path
+
+	return s
+}
+
+func (s StateColumnsModel) getApplication() *Application {
+	return s.Application
+}
+
+func (s StateColumnsModel) refresh() (_ stateModel, cmd tea.Cmd) {
+	return s, nil
+}
+
+// String implements fmt.Stringer.
+func (s StateColumnsModel) String() string {
+	return modelValue(s)
+}
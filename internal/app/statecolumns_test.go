@@ -0,0 +1,144 @@
+package app_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/app"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+)
+
+const columnsTestLog = `{"time":"1970-01-01T00:00:00.00","level":"INFO","message":"first","user":{"id":"u-42"}}
+{"time":"1970-01-01T00:00:01.00","level":"ERROR","message":"second","user":{"id":"u-43"}}
+`
+
+func TestStateColumns(t *testing.T) {
+	t.Parallel()
+
+	openColumns := func(t *testing.T, model tea.Model) tea.Model {
+		t.Helper()
+
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'C'}})
+
+		_, ok := model.(app.StateColumnsModel)
+		require.Truef(t, ok, "%s", model)
+
+		view := model.View()
+		assert.Contains(t, view, "> Time")
+		assert.Contains(t, view, "$.message")
+
+		return model
+	}
+
+	typeRunes := func(model tea.Model, value string) tea.Model {
+		for _, r := range value {
+			model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+
+		return model
+	}
+
+	t.Run("add", func(t *testing.T) {
+		t.Parallel()
+
+		model := openColumns(t, newTestModel(t, []byte(columnsTestLog)))
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+		assert.Contains(t, model.View(), "JSONPath")
+
+		model = typeRunes(model, "user.id")
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+		assert.Contains(t, model.View(), "> id")
+		assert.Contains(t, model.View(), "$.user.id")
+
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEsc})
+
+		_, ok := model.(app.StateLoadedModel)
+		require.Truef(t, ok, "%s", model)
+		assert.Contains(t, model.View(), "u-42")
+	})
+
+	t.Run("invalid_path", func(t *testing.T) {
+		t.Parallel()
+
+		model := openColumns(t, newTestModel(t, []byte(columnsTestLog)))
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+		model = typeRunes(model, "$[")
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+		assert.Contains(t, model.View(), "Invalid path")
+	})
+
+	t.Run("hide_and_move", func(t *testing.T) {
+		t.Parallel()
+
+		model := openColumns(t, newTestModel(t, []byte(columnsTestLog)))
+
+		// Time is hidden, Level is moved after Message.
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+		assert.Contains(t, model.View(), "> Level")
+
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'J'}})
+		assert.Contains(t, model.View(), "> Level")
+
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEsc})
+
+		_, ok := model.(app.StateLoadedModel)
+		require.Truef(t, ok, "%s", model)
+
+		view := model.View()
+		assert.NotContains(t, view, "Time")
+		assert.Less(t, strings.Index(view, "Message"), strings.Index(view, "Level"))
+	})
+
+	t.Run("last_column", func(t *testing.T) {
+		t.Parallel()
+
+		model := openColumns(t, newTestModel(t, []byte(columnsTestLog)))
+
+		for range 3 {
+			model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+		}
+
+		assert.Contains(t, model.View(), "The last column can't be hidden")
+	})
+
+	t.Run("width", func(t *testing.T) {
+		t.Parallel()
+
+		model := openColumns(t, newTestModel(t, []byte(columnsTestLog)))
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyDown})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyDown})
+		assert.Contains(t, model.View(), "message      auto")
+
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'-'}})
+		assert.NotContains(t, model.View(), "message      auto")
+
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'0'}})
+		assert.Contains(t, model.View(), "message      auto")
+	})
+
+	t.Run("save", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := filepath.Join(t.TempDir(), config.FileName)
+		require.NoError(t, os.WriteFile(configPath, []byte(`{"isReverseDefault": false}`), 0o600))
+
+		model := openColumns(t, newTestModel(t, []byte(columnsTestLog), func(cfg *config.Config) {
+			cfg.Path = configPath
+		}))
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+		assert.Contains(t, model.View(), "Saved to "+configPath)
+
+		cfg, err := config.Read(configPath)
+		require.NoError(t, err)
+		assert.False(t, cfg.IsReverseDefault)
+		require.Len(t, cfg.Fields, 2)
+		assert.Equal(t, "Level", cfg.Fields[0].Title)
+	})
+}
@@ -0,0 +1,212 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hedhyw/bubbles/key"
+	"github.com/hedhyw/bubbles/textinput"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/events"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/export"
+)
+
+// exportProgressInterval is an interval of refreshing the export progress.
+const exportProgressInterval = 100 * time.Millisecond
+
+type (
+	// exportProgressMsg is a trigger to refresh the export progress.
+	exportProgressMsg struct{}
+
+	// exportFinishedMsg is an event about the finished export.
+	exportFinishedMsg struct{ Err error }
+)
+
+// StateExportingModel is a state that prompts for a path and exports
+// entries of the previous state to it in background.
+type StateExportingModel struct {
+	*Application
+
+	previousState stateModel
+	table         logsTableModel
+
+	textInput textinput.Model
+
+	// job is nil until the export is started.
+	job    *export.Job
+	cancel context.CancelFunc
+	path   string
+
+	finished bool
+	err      error
+}
+
+func newStateExporting(
+	previousState stateModel,
+	table logsTableModel,
+) StateExportingModel {
+	textInput := textinput.New()
+	textInput.Prompt = "Export to: "
+	textInput.Placeholder = "export.log, export.csv, export.tsv or export.md"
+	textInput.Focus()
+
+	return StateExportingModel{
+		Application: previousState.getApplication(),
+
+		previousState: previousState,
+		table:         table,
+
+		textInput: textInput,
+	}
+}
+
+// Init initializes component. It implements tea.Model.
+func (s StateExportingModel) Init() tea.Cmd {
+	return nil
+}
+
+// View renders component. It implements tea.Model.
+func (s StateExportingModel) View() string {
+	return s.BaseStyle.Render(s.table.View()) + "\n" + s.viewFooter()
+}
+
+func (s StateExportingModel) viewFooter() string {
+	switch {
+	case s.job == nil:
+		return s.textInput.View()
+	case !s.finished:
+		return s.FooterStyle.Render(fmt.Sprintf(
+			"Exporting %d/%d entries to %s...",
+			s.job.Written(), s.job.Total(), s.path,
+		))
+	case errors.Is(s.err, context.Canceled):
+		return s.FooterStyle.Render("Export is canceled. Press any key to continue.")
+	case s.err != nil:
+		return s.FooterStyle.Render(fmt.Sprintf("Export failed: %s. Press any key to continue.", s.err))
+	default:
+		return s.FooterStyle.Render(fmt.Sprintf(
+			"Exported %d entries to %s. Press any key to continue.",
+			s.job.Written(), s.path,
+		))
+	}
+}
+
+// Update handles events. It implements tea.Model.
+func (s StateExportingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmdBatch []tea.Cmd
+
+	s.Application.Update(msg)
+
+	switch msg := msg.(type) {
+	case exportProgressMsg:
+		if s.finished {
+			return s, nil
+		}
+
+		return s, exportProgressTick()
+	case exportFinishedMsg:
+		s.finished = true
+		s.err = msg.Err
+
+		return s, nil
+	case tea.KeyMsg:
+		return s.handleKeyMsg(msg)
+	case events.LogEntriesUpdateMsg:
+		// The table is frozen, it shows the entries that are exported.
+		return s, nil
+	default:
+		s.table, cmdBatch = batched(s.table.Update(msg))(cmdBatch)
+	}
+
+	var cmd tea.Cmd
+
+	s.textInput, cmd = s.textInput.Update(msg)
+	cmdBatch = appendCmd(cmdBatch, cmd)
+
+	return s, tea.Batch(cmdBatch...)
+}
+
+func (s StateExportingModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, s.keys.Exit):
+		if s.cancel != nil {
+			s.cancel()
+		}
+
+		return s, tea.Quit
+	case s.finished:
+		return s.previousState.refresh()
+	case s.job != nil:
+		if key.Matches(msg, s.keys.Back) {
+			s.cancel()
+		}
+
+		return s, nil
+	case key.Matches(msg, s.keys.Back) && msg.Type != tea.KeyRunes:
+		return s.previousState.refresh()
+	case key.Matches(msg, s.keys.Open):
+		return s.handleEnterKeyClickedMsg()
+	default:
+		var cmd tea.Cmd
+
+		s.textInput, cmd = s.textInput.Update(msg)
+
+		return s, cmd
+	}
+}
+
+func (s StateExportingModel) handleEnterKeyClickedMsg() (tea.Model, tea.Cmd) {
+	path := s.textInput.Value()
+	if path == "" {
+		return s, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	job := &export.Job{
+		Entries: s.table.logEntries,
+		Config:  s.Config,
+		Format:  export.FormatFromPath(path),
+		Reverse: s.table.lazyTable.reverse,
+		Notes:   maps.Clone(s.notes),
+	}
+
+	s.job = job
+	s.cancel = cancel
+	s.path = path
+	s.textInput.Blur()
+
+	return s, tea.Batch(
+		exportProgressTick(),
+		func() tea.Msg {
+			defer cancel()
+
+			return exportFinishedMsg{Err: job.WriteFile(ctx, path)}
+		},
+	)
+}
+
+func exportProgressTick() tea.Cmd {
+	return tea.Tick(exportProgressInterval, func(time.Time) tea.Msg {
+		return exportProgressMsg{}
+	})
+}
+
+func (s StateExportingModel) getApplication() *Application {
+	return s.Application
+}
+
+func (s StateExportingModel) refresh() (_ stateModel, cmd tea.Cmd) {
+	s.table, cmd = s.table.Update(s.LastWindowSize())
+
+	return s, cmd
+}
+
+// String implements fmt.Stringer.
+func (s StateExportingModel) String() string {
+	return modelValue(s)
+}
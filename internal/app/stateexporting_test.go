@@ -0,0 +1,146 @@
+package app_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/app"
+)
+
+func TestStateExporting(t *testing.T) {
+	t.Parallel()
+
+	const (
+		termIncluded = "included"
+		termExcluded = "excluded"
+	)
+
+	const jsonFile = `{"time":"1970-01-01T00:00:00.00","level":"INFO","message": "` + termIncluded + `"}
+{"time":"1970-01-01T00:00:00.00","level":"INFO","message": "` + termExcluded + `"}
+`
+
+	openExport := func(tb testing.TB, model tea.Model) tea.Model {
+		tb.Helper()
+
+		model = handleUpdate(model, tea.KeyMsg{
+			Type:  tea.KeyRunes,
+			Runes: []rune{'e'},
+		})
+
+		_, ok := model.(app.StateExportingModel)
+		require.Truef(tb, ok, "%s", model)
+
+		return model
+	}
+
+	export := func(tb testing.TB, model tea.Model, path string) tea.Model {
+		tb.Helper()
+
+		model = handleUpdate(model, tea.KeyMsg{
+			Type:  tea.KeyRunes,
+			Runes: []rune(path),
+		})
+
+		return handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+	}
+
+	t.Run("all", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "export.log")
+
+		model := openExport(t, newTestModel(t, []byte(jsonFile)))
+		model = export(t, model, path)
+
+		assert.Contains(t, model.View(), "Exported 2 entries")
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		assert.Contains(t, string(content), termIncluded)
+		assert.Contains(t, string(content), termExcluded)
+
+		// Any key returns to the previous state.
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyDown})
+
+		_, ok := model.(app.StateLoadedModel)
+		assert.Truef(t, ok, "%s", model)
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "export.csv")
+
+		model := newTestModel(t, []byte(jsonFile))
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(termIncluded)})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+
+		model = openExport(t, model)
+		model = export(t, model, path)
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Time,Level,Message\n1970-01-01T00:00:00.00,info,"+termIncluded+"\n", string(content))
+
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEsc})
+
+		_, ok := model.(app.StateFilteredModel)
+		assert.Truef(t, ok, "%s", model)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "not_found", "export.log")
+
+		model := openExport(t, newTestModel(t, []byte(jsonFile)))
+		model = export(t, model, path)
+
+		assert.Contains(t, model.View(), "Export failed")
+	})
+
+	t.Run("back", func(t *testing.T) {
+		t.Parallel()
+
+		model := openExport(t, newTestModel(t, []byte(jsonFile)))
+
+		// "q" is a part of the path.
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+
+		_, ok := model.(app.StateExportingModel)
+		require.Truef(t, ok, "%s", model)
+
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEsc})
+
+		_, ok = model.(app.StateLoadedModel)
+		assert.Truef(t, ok, "%s", model)
+	})
+
+	t.Run("empty_path", func(t *testing.T) {
+		t.Parallel()
+
+		model := openExport(t, newTestModel(t, []byte(jsonFile)))
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+
+		_, ok := model.(app.StateExportingModel)
+		assert.Truef(t, ok, "%s", model)
+		assert.Contains(t, model.View(), "Export to:")
+	})
+
+	t.Run("exit", func(t *testing.T) {
+		t.Parallel()
+
+		model := openExport(t, newTestModel(t, []byte(jsonFile)))
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+		requireCmdMsg(t, tea.Quit(), cmd)
+	})
+}
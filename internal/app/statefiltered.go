@@ -7,6 +7,7 @@ import (
 	"github.com/hedhyw/bubbles/key"
 
 	"github.com/hedhyw/json-log-viewer/internal/pkg/events"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/session"
 	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
 )
 
@@ -20,12 +21,20 @@ type StateFilteredModel struct {
 
 	filterText  string
 	filterField string
+	// markedOnly shows only marked entries.
+	markedOnly bool
+	// minLevel hides less severe entries if it is set.
+	minLevel source.Level
+	// selectIndex is the index of the entry in the whole log that is
+	// selected after filtering. It is -1 if the first entry is selected.
+	selectIndex int
 }
 
 func newStateFiltered(
 	previousState StateLoadedModel,
 	filterText string,
 	filterField string,
+	markedOnly bool,
 ) StateFilteredModel {
 	return StateFilteredModel{
 		Application: previousState.Application,
@@ -34,6 +43,8 @@ func newStateFiltered(
 
 		filterText:  filterText,
 		filterField: filterField,
+		markedOnly:  markedOnly,
+		selectIndex: -1,
 	}
 }
 
@@ -47,12 +58,40 @@ func (s StateFilteredModel) Init() tea.Cmd {
 // View renders component. It implements tea.Model.
 func (s StateFilteredModel) View() string {
 	var msg string
-	if s.filterField == "" {
+	switch {
+	case s.filterText == "" && s.markedOnly:
+		msg = fmt.Sprintf("marked %d", s.logEntries.Len())
+	case s.filterText == "":
+		msg = fmt.Sprintf("filtered %d", s.logEntries.Len())
+	case s.filterField == "":
 		msg = fmt.Sprintf("filtered %d by: %s", s.logEntries.Len(), s.filterText)
-	} else {
+	default:
 		msg = fmt.Sprintf("filtered %d by field: %s, query: %s", s.logEntries.Len(), s.filterField, s.filterText)
 	}
 
+	if s.filterText != "" && s.markedOnly {
+		msg += ", marked only"
+	}
+
+	if s.minLevel != "" {
+		msg += ", level: " + string(s.minLevel) + "+"
+	}
+
+	switch {
+	case s.table.isSorting():
+		msg += ", sorting"
+	case !s.sort.IsZero():
+		msg += ", sort: " + s.sort.String()
+	}
+
+	if s.timeMode != timeModeAbsolute {
+		msg += ", time: " + s.timeMode.String()
+	}
+
+	if s.viewName != "" {
+		msg = "view " + s.viewName + ": " + msg
+	}
+
 	footer := s.FooterStyle.Render(msg)
 
 	return s.BaseStyle.Render(s.table.View()) + "\n" + footer
@@ -66,12 +105,20 @@ func (s StateFilteredModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	if _, ok := msg.(*StateFilteredModel); ok {
 		s, msg = s.handleStateFilteredModel()
+		s.table, cmdBatch = batched(s.table.refreshSort())(cmdBatch)
 	}
 
 	if _, ok := msg.(events.LogEntriesUpdateMsg); ok {
 		return s, nil
 	}
 
+	s.rememberView(s.table, session.State{
+		FilterText:  s.filterText,
+		FilterField: s.filterField,
+		MarkedOnly:  s.markedOnly,
+		MinLevel:    string(s.minLevel),
+	})
+
 	switch typedMsg := msg.(type) {
 	case events.ErrorOccuredMsg:
 		return s.handleErrorOccuredMsg(typedMsg)
@@ -94,6 +141,34 @@ func (s StateFilteredModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return s.handleBackKeyClickedMsg()
 	case key.Matches(msg, s.keys.Filter):
 		return s.handleFilterKeyClickedMsg()
+	case key.Matches(msg, s.keys.Export):
+		return initializeModel(newStateExporting(s, s.table))
+	case key.Matches(msg, s.keys.Annotate):
+		return initializeModel(newStateAnnotating(s, s.table))
+	case key.Matches(msg, s.keys.MarkedOnly):
+		state := newStateFiltered(
+			s.previousState,
+			s.filterText,
+			s.filterField,
+			!s.markedOnly,
+		)
+		state.minLevel = s.minLevel
+
+		return initializeModel(state)
+	case key.Matches(msg, s.keys.Views):
+		return initializeModel(newStateViews(s.previousState, s))
+	case key.Matches(msg, s.keys.Columns):
+		return initializeModel(newStateColumns(s))
+	case key.Matches(msg, s.keys.ShowPreview):
+		s.preview = !s.preview
+
+		return s.refresh()
+	case key.Matches(msg, s.keys.TimeMode):
+		s.timeMode = s.timeMode.next()
+
+		return s.refresh()
+	case key.Matches(msg, s.keys.Sort):
+		return initializeModel(newStateSort(s))
 	case key.Matches(msg, s.keys.ToggleViewArrow), key.Matches(msg, s.keys.Open):
 		return s.handleRequestOpenJSON()
 	default:
@@ -103,8 +178,8 @@ func (s StateFilteredModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 func (s StateFilteredModel) handleBackKeyClickedMsg() (tea.Model, tea.Cmd) {
 	cursor := s.table.Cursor()
-	if cursor >= 0 && cursor < s.logEntries.Len() {
-		entry := s.logEntries.LogEntry(s.Config, cursor)
+	if cursor >= 0 && cursor < s.table.logEntries.Len() {
+		entry := s.table.logEntries.LogEntry(s.Config, cursor)
 		s.previousState.table = s.previousState.table.Select(entry.Index)
 	}
 
@@ -117,6 +192,17 @@ func (s StateFilteredModel) handleStateFilteredModel() (StateFilteredModel, tea.
 		return s, events.ShowError(err)()
 	}
 
+	if s.markedOnly {
+		entries = entries.FilterByIndex(s.marks.has)
+	}
+
+	if s.minLevel != "" {
+		entries, err = entries.FilterByLevel(s.minLevel, s.Config)
+		if err != nil {
+			return s, events.ShowError(err)()
+		}
+	}
+
 	s.logEntries = entries
 	s.table = newLogsTableModel(
 		s.Application,
@@ -125,6 +211,10 @@ func (s StateFilteredModel) handleStateFilteredModel() (StateFilteredModel, tea.
 		s.previousState.table.lazyTable.reverse,
 	)
 
+	if s.selectIndex >= 0 {
+		s.table = s.table.SelectIndex(s.selectIndex)
+	}
+
 	return s, nil
 }
 
@@ -138,17 +228,30 @@ func (s StateFilteredModel) handleRequestOpenJSON() (tea.Model, tea.Cmd) {
 		return s, events.EscKeyClicked
 	}
 
-	return s, events.OpenJSONRowRequested(s.logEntries, s.table.Cursor())
+	return s, events.OpenJSONRowRequested(s.table.logEntries, s.table.Cursor())
 }
 
 func (s StateFilteredModel) getApplication() *Application {
 	return s.Application
 }
 
+func (s StateFilteredModel) getTable() logsTableModel {
+	return s.table
+}
+
+func (s StateFilteredModel) withTable(table logsTableModel) stateModel {
+	s.table = table
+
+	return s
+}
+
 func (s StateFilteredModel) refresh() (_ stateModel, cmd tea.Cmd) {
+	var cmdSort tea.Cmd
+
+	s.table, cmdSort = s.table.refreshSort()
 	s.table, cmd = s.table.Update(s.LastWindowSize())
 
-	return s, cmd
+	return s, tea.Batch(cmd, cmdSort, s.table.tickRelativeTime())
 }
 
 // String implements fmt.Stringer.
@@ -118,7 +118,7 @@ func (s StateFilteringModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (s StateFilteringModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
-	case key.Matches(msg, s.keys.Back) && string(msg.Runes) != "q":
+	case key.Matches(msg, s.keys.Back) && msg.Type != tea.KeyRunes:
 		return s.previousState.refresh()
 	case key.Matches(msg, s.keys.Open):
 		return s.handleEnterKeyClickedMsg()
@@ -146,6 +146,7 @@ func (s StateFilteringModel) handleEnterKeyClickedMsg() (tea.Model, tea.Cmd) {
 		s.previousState,
 		input,
 		filterField,
+		false, // markedOnly.
 	))
 }
 
@@ -19,6 +19,10 @@ func newStateInitial(application *Application) StateInitialModel {
 
 // Init initializes component. It implements tea.Model.
 func (s StateInitialModel) Init() tea.Cmd {
+	if s.configErr != nil {
+		return events.ShowError(s.configErr)
+	}
+
 	return nil
 }
 
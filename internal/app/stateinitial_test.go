@@ -45,6 +45,25 @@ func TestStateInitial(t *testing.T) {
 		assert.Nil(t, model.Init())
 	})
 
+	t.Run("invalid_highlight", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.GetDefaultConfig()
+		cfg.Highlights = []config.Highlight{{Term: "/(/"}}
+
+		model := app.NewModel("-", cfg, testVersion)
+
+		cmd := model.Init()
+		require.NotNil(t, cmd)
+
+		msg, ok := cmd().(events.ErrorOccuredMsg)
+		require.Truef(t, ok, "%s", model)
+		require.ErrorIs(t, msg.Err, source.ErrInvalidHighlight)
+
+		_, ok = handleUpdate(model, msg).(app.StateErrorModel)
+		assert.Truef(t, ok, "%s", model)
+	})
+
 	t.Run("Unknown_Event", func(t *testing.T) {
 		t.Parallel()
 
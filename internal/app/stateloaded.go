@@ -9,6 +9,7 @@ import (
 	"github.com/hedhyw/bubbles/key"
 
 	"github.com/hedhyw/json-log-viewer/internal/pkg/events"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/session"
 	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
 )
 
@@ -52,7 +53,26 @@ func (s StateLoadedModel) viewTable() string {
 }
 
 func (s StateLoadedModel) toggles() string {
-	toggles := make([]string, 0, 3)
+	toggles := make([]string, 0, 7)
+
+	if s.Config.Profile != "" {
+		toggles = append(toggles, "profile: "+s.Config.Profile)
+	}
+
+	if s.viewName != "" {
+		toggles = append(toggles, "view: "+s.viewName)
+	}
+
+	switch {
+	case s.table.isSorting():
+		toggles = append(toggles, "sorting")
+	case !s.sort.IsZero():
+		toggles = append(toggles, "sort: "+s.sort.String())
+	}
+
+	if s.timeMode != timeModeAbsolute {
+		toggles = append(toggles, "time: "+s.timeMode.String())
+	}
 
 	if s.table.lazyTable.reverse {
 		toggles = append(toggles, "reverse")
@@ -73,20 +93,16 @@ func (s StateLoadedModel) toggles() string {
 
 func (s StateLoadedModel) viewHelp() string {
 	if s.help.ShowAll {
-		toggleText := lipgloss.NewStyle().
-			Background(lipgloss.Color("#353533")).
+		toggleText := s.theme.footer.apply(lipgloss.NewStyle()).
 			Padding(0, 1).
 			Render(s.toggles())
 
-		versionText := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFDF5")).
-			Background(lipgloss.Color("#6124DF")).
+		versionText := s.theme.version.apply(lipgloss.NewStyle()).
 			Padding(0, 1).
 			Render(s.Version)
 
 		width := s.LastWindowSize().Width
-		fillerText := lipgloss.NewStyle().
-			Background(lipgloss.Color("#353533")).
+		fillerText := s.theme.footer.apply(lipgloss.NewStyle()).
 			Width(width - lipgloss.Width(toggleText) - lipgloss.Width(versionText)).
 			Render("")
 
@@ -108,6 +124,7 @@ func (s StateLoadedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmdBatch []tea.Cmd
 
 	s.Application.Update(msg)
+	s.rememberView(s.table, session.State{})
 
 	switch msg := msg.(type) {
 	case events.ErrorOccuredMsg:
@@ -120,6 +137,26 @@ func (s StateLoadedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return s, tea.Quit
 		case key.Matches(msg, s.keys.Filter):
 			return s.handleFilterKeyClickedMsg()
+		case key.Matches(msg, s.keys.Export):
+			return initializeModel(newStateExporting(s, s.table))
+		case key.Matches(msg, s.keys.MarkedOnly):
+			return initializeModel(newStateFiltered(s, "", "", true))
+		case key.Matches(msg, s.keys.Annotate):
+			return initializeModel(newStateAnnotating(s, s.table))
+		case key.Matches(msg, s.keys.Views):
+			return initializeModel(newStateViews(s, s))
+		case key.Matches(msg, s.keys.Columns):
+			return initializeModel(newStateColumns(s))
+		case key.Matches(msg, s.keys.ShowPreview):
+			s.preview = !s.preview
+
+			return s.refresh()
+		case key.Matches(msg, s.keys.TimeMode):
+			s.timeMode = s.timeMode.next()
+
+			return s.refresh()
+		case key.Matches(msg, s.keys.Sort):
+			return initializeModel(newStateSort(s))
 		case key.Matches(msg, s.keys.ToggleViewArrow), key.Matches(msg, s.keys.Open):
 			return s.handleRequestOpenJSON()
 		case key.Matches(msg, s.keys.ToggleFullHelp):
@@ -136,6 +173,10 @@ func (s StateLoadedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	s.table, cmdBatch = batched(s.table.Update(msg))(cmdBatch)
 
+	if update, ok := msg.(events.LogEntriesUpdateMsg); ok && update.Loaded && s.restore != nil {
+		return s.restoreSession(tea.Batch(cmdBatch...))
+	}
+
 	return s, tea.Batch(cmdBatch...)
 }
 
@@ -148,7 +189,7 @@ func (s StateLoadedModel) handleKeyMsg(msg tea.KeyMsg) []tea.Cmd {
 }
 
 func (s StateLoadedModel) handleRequestOpenJSON() (tea.Model, tea.Cmd) {
-	return s, events.OpenJSONRowRequested(s.entries, s.table.Cursor())
+	return s, events.OpenJSONRowRequested(s.table.logEntries, s.table.Cursor())
 }
 
 func (s StateLoadedModel) handleFilterKeyClickedMsg() (tea.Model, tea.Cmd) {
@@ -159,13 +200,30 @@ func (s StateLoadedModel) getApplication() *Application {
 	return s.Application
 }
 
+func (s StateLoadedModel) getTable() logsTableModel {
+	return s.table
+}
+
+func (s StateLoadedModel) withTable(table logsTableModel) stateModel {
+	s.table = table
+
+	return s
+}
+
 func (s StateLoadedModel) refresh() (_ stateModel, cmd tea.Cmd) {
-	var cmdFirst, cmdSecond tea.Cmd
+	s, cmd = s.refreshTable()
+
+	return s, tea.Batch(cmd, s.table.tickRelativeTime())
+}
+
+func (s StateLoadedModel) refreshTable() (StateLoadedModel, tea.Cmd) {
+	var cmdFirst, cmdSecond, cmdSort tea.Cmd
 
+	s.table, cmdSort = s.table.refreshSort()
 	s.table, cmdSecond = s.table.Update(s.LastWindowSize())
 	s.table, cmdFirst = s.table.Update(events.LogEntriesUpdateMsg(s.Entries()))
 
-	return s, tea.Batch(cmdFirst, cmdSecond)
+	return s, tea.Batch(cmdFirst, cmdSecond, cmdSort)
 }
 
 // String implements fmt.Stringer.
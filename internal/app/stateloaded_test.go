@@ -122,6 +122,17 @@ func TestStateLoaded(t *testing.T) {
 		assert.NotContains(t, view, "reverse")
 	})
 
+	t.Run("label_profile", func(t *testing.T) {
+		t.Parallel()
+
+		model := setup(func(cfg *config.Config) {
+			cfg.Profile = "gcp"
+		})
+
+		view := model.View()
+		assert.Contains(t, view, "profile: gcp")
+	})
+
 	t.Run("label_not_reverse", func(t *testing.T) {
 		t.Parallel()
 
@@ -295,3 +306,37 @@ func BenchmarkStateLoadedBig(b *testing.B) {
 
 	model.Update(events.LogEntriesUpdateMsg(logEntries))
 }
+
+func TestStateLoadedCustomKeys(t *testing.T) {
+	t.Parallel()
+
+	const jsonFile = `{"time":"1970-01-01T00:00:00.00","level":"INFO","message": "test"}`
+
+	model := newTestModel(t, []byte(jsonFile), func(cfg *config.Config) {
+		cfg.Keys = map[string][]string{
+			"filter": {"/"},
+			"back":   {"esc", "x"},
+		}
+	})
+
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	_, ok := model.(app.StateLoadedModel)
+	require.Truef(t, ok, "%s", model)
+
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	assert.Contains(t, model.View(), "/ Filter")
+
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	_, ok = model.(app.StateFilteringModel)
+	require.Truef(t, ok, "%s", model)
+
+	// The printable back key is typed into the filter.
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	_, ok = model.(app.StateFilteringModel)
+	require.Truef(t, ok, "%s", model)
+	assert.Contains(t, model.View(), "x")
+
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEsc})
+	_, ok = model.(app.StateLoadedModel)
+	require.Truef(t, ok, "%s", model)
+}
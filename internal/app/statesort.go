@@ -0,0 +1,243 @@
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hedhyw/bubbles/key"
+	"github.com/hedhyw/bubbles/textinput"
+	"github.com/yalp/jsonpath"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/events"
+)
+
+// Titles of items of the sort menu that are not columns.
+const (
+	logOrderTitle = "(log order)"
+	jsonPathTitle = "JSONPath..."
+)
+
+// StateSortModel is a state that selects the column or the JSONPath to sort
+// entries by. Selecting the current sort again reverses it.
+type StateSortModel struct {
+	*Application
+
+	previousState tableStateModel
+
+	// fields are titles of columns and JSONPaths, the first one is the order
+	// of the log and the last one asks for a JSONPath.
+	fields []string
+	cursor int
+
+	// typing is true while the JSONPath is typed.
+	typing    bool
+	textInput textinput.Model
+	status    string
+}
+
+func newStateSort(previousState tableStateModel) StateSortModel {
+	app := previousState.getApplication()
+
+	fields := make([]string, 0, len(app.Config.Fields)+3)
+	fields = append(fields, logOrderTitle)
+
+	for _, f := range app.Config.Fields {
+		fields = append(fields, f.Title)
+	}
+
+	if strings.HasPrefix(app.sort.Field, "$") {
+		fields = append(fields, app.sort.Field)
+	}
+
+	fields = append(fields, jsonPathTitle)
+
+	cursor := 0
+
+	for i, field := range fields {
+		if i > 0 && strings.EqualFold(field, app.sort.Field) {
+			cursor = i
+		}
+	}
+
+	textInput := textinput.New()
+	textInput.Prompt = "JSONPath: "
+	textInput.Placeholder = "$.duration, tab completes observed keys"
+	textInput.ShowSuggestions = true
+	textInput.SetSuggestions(app.Entries().ObservedKeys(app.Config))
+
+	return StateSortModel{
+		Application: app,
+
+		previousState: previousState,
+
+		fields: fields,
+		cursor: cursor,
+
+		textInput: textInput,
+	}
+}
+
+// Init initializes component. It implements tea.Model.
+func (s StateSortModel) Init() tea.Cmd {
+	return nil
+}
+
+// View renders component. It implements tea.Model.
+func (s StateSortModel) View() string {
+	var list strings.Builder
+
+	for i, field := range s.fields {
+		if i == s.cursor {
+			list.WriteString("> " + field)
+		} else {
+			list.WriteString("  " + field)
+		}
+
+		switch {
+		case i == 0 || i == len(s.fields)-1 || !strings.EqualFold(field, s.sort.Field):
+		case s.sort.Descending:
+			list.WriteString(" ↓")
+		default:
+			list.WriteString(" ↑")
+		}
+
+		if i < len(s.fields)-1 {
+			list.WriteString("\n")
+		}
+	}
+
+	windowSize := s.LastWindowSize()
+	x, y := s.BaseStyle.GetFrameSize()
+
+	return s.BaseStyle.
+		Width(max(windowSize.Width-x, 1)).
+		Height(max(windowSize.Height-y-footerSize, 1)).
+		Render(list.String()) + "\n" + s.viewFooter()
+}
+
+func (s StateSortModel) viewFooter() string {
+	switch {
+	case s.typing && s.status != "":
+		return s.textInput.View() + s.FooterStyle.Render(s.status)
+	case s.typing:
+		return s.textInput.View()
+	default:
+		return s.FooterStyle.Render("Sort by: enter to sort, enter again to reverse, esc to go back")
+	}
+}
+
+// Update handles events. It implements tea.Model.
+func (s StateSortModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	s.Application.Update(msg)
+
+	switch msg := msg.(type) {
+	case events.ErrorOccuredMsg:
+		return s.handleErrorOccuredMsg(msg)
+	case tea.KeyMsg:
+		if key.Matches(msg, s.keys.Exit) {
+			return s, tea.Quit
+		}
+
+		if s.typing {
+			return s.handleTypingKeyMsg(msg)
+		}
+
+		switch {
+		case key.Matches(msg, s.keys.Back):
+			return s.previousState.refresh()
+		case key.Matches(msg, s.keys.Up):
+			s.cursor = max(s.cursor-1, 0)
+		case key.Matches(msg, s.keys.Down):
+			s.cursor = min(s.cursor+1, len(s.fields)-1)
+		case key.Matches(msg, s.keys.Open):
+			return s.handleOpenKeyMsg()
+		}
+	}
+
+	if s.typing {
+		var cmd tea.Cmd
+
+		s.textInput, cmd = s.textInput.Update(msg)
+
+		return s, cmd
+	}
+
+	return s, nil
+}
+
+func (s StateSortModel) handleOpenKeyMsg() (tea.Model, tea.Cmd) {
+	switch field := s.fields[s.cursor]; {
+	case s.cursor == 0:
+		return s.sortBy("")
+	case s.cursor == len(s.fields)-1:
+		s.typing = true
+		s.textInput.SetValue("")
+
+		return s, s.textInput.Focus()
+	default:
+		return s.sortBy(field)
+	}
+}
+
+func (s StateSortModel) handleTypingKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, s.keys.Back) && msg.Type != tea.KeyRunes:
+		s.typing = false
+		s.status = ""
+		s.textInput.Blur()
+
+		return s, nil
+	case key.Matches(msg, s.keys.Open):
+		path := strings.TrimSpace(s.textInput.Value())
+		if path == "" {
+			return s, nil
+		}
+
+		if !strings.HasPrefix(path, "$") {
+			path = "$." + path
+		}
+
+		if _, err := jsonpath.Prepare(path); err != nil {
+			s.status = "Invalid path: " + err.Error()
+
+			return s, nil
+		}
+
+		return s.sortBy(path)
+	default:
+		var cmd tea.Cmd
+
+		s.status = ""
+		s.textInput, cmd = s.textInput.Update(msg)
+
+		return s, cmd
+	}
+}
+
+// sortBy sorts entries by the field, the empty field restores the order of
+// the log. The current sort is reversed.
+func (s StateSortModel) sortBy(field string) (tea.Model, tea.Cmd) {
+	order := config.Sort{Field: field}
+
+	if field != "" && strings.EqualFold(field, s.sort.Field) {
+		order.Descending = !s.sort.Descending
+	}
+
+	s.sort = order
+
+	return s.previousState.refresh()
+}
+
+func (s StateSortModel) getApplication() *Application {
+	return s.Application
+}
+
+func (s StateSortModel) refresh() (_ stateModel, cmd tea.Cmd) {
+	return s, nil
+}
+
+// String implements fmt.Stringer.
+func (s StateSortModel) String() string {
+	return modelValue(s)
+}
@@ -0,0 +1,203 @@
+package app_test
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/app"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/events"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/tests"
+)
+
+const sortTestLog = `{"time":"1970-01-01T00:00:02.00","level":"INFO","message":"beta","duration":30}
+{"time":"1970-01-01T00:00:00.00","level":"ERROR","message":"alpha","duration":5}
+{"time":"1970-01-01T00:00:01.00","level":"WARN","message":"gamma","duration":100}
+`
+
+func setNotReversed(cfg *config.Config) {
+	cfg.IsReverseDefault = false
+}
+
+func TestStateSort(t *testing.T) {
+	t.Parallel()
+
+	openSort := func(t *testing.T, model tea.Model) tea.Model {
+		t.Helper()
+
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+
+		_, ok := model.(app.StateSortModel)
+		require.Truef(t, ok, "%s", model)
+
+		return model
+	}
+
+	// selectField selects the item of the sort menu by its position.
+	selectField := func(model tea.Model, position int) tea.Model {
+		for range position {
+			model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyDown})
+		}
+
+		return handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+	}
+
+	t.Run("column", func(t *testing.T) {
+		t.Parallel()
+
+		model := openSort(t, newTestModel(t, []byte(sortTestLog), setNotReversed))
+		assert.Contains(t, model.View(), "> (log order)")
+
+		// Time.
+		model = selectField(model, 1)
+
+		_, ok := model.(app.StateLoadedModel)
+		require.Truef(t, ok, "%s", model)
+
+		view := model.View()
+		assert.Contains(t, view, "sort: Time asc")
+		assertOrder(t, view, "alpha", "gamma", "beta")
+
+		// Selecting the same column reverses the order.
+		model = openSort(t, model)
+		assert.Contains(t, model.View(), "> Time ↑")
+
+		model = selectField(model, 0)
+		assertOrder(t, model.View(), "beta", "gamma", "alpha")
+
+		// The order of the log is restored.
+		model = openSort(t, model)
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyUp})
+		model = selectField(model, 0)
+		assert.NotContains(t, model.View(), "sort:")
+		assertOrder(t, model.View(), "beta", "alpha", "gamma")
+	})
+
+	t.Run("level", func(t *testing.T) {
+		t.Parallel()
+
+		model := openSort(t, newTestModel(t, []byte(sortTestLog), setNotReversed))
+		model = selectField(model, 2)
+		assertOrder(t, model.View(), "beta", "gamma", "alpha")
+
+		model = selectField(openSort(t, model), 0)
+		assertOrder(t, model.View(), "alpha", "gamma", "beta")
+	})
+
+	t.Run("json_path", func(t *testing.T) {
+		t.Parallel()
+
+		model := openSort(t, newTestModel(t, []byte(sortTestLog), setNotReversed))
+		model = selectField(model, 4)
+		assert.Contains(t, model.View(), "JSONPath:")
+
+		for _, r := range "duration" {
+			model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+
+		view := model.View()
+		assert.Contains(t, view, "sort: $.duration asc")
+		assertOrder(t, view, "alpha", "beta", "gamma")
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		t.Parallel()
+
+		model := newTestModel(t, []byte(sortTestLog), setNotReversed)
+		model = selectField(openSort(t, model), 1)
+
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+
+		for _, r := range "a" {
+			model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+
+		_, ok := model.(app.StateFilteredModel)
+		require.Truef(t, ok, "%s", model)
+
+		view := model.View()
+		assert.Contains(t, view, "sort: Time asc")
+		assertOrder(t, view, "alpha", "gamma", "beta")
+
+		// The entry of the sorted table is opened.
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyHome})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+		assert.Contains(t, model.View(), `"alpha"`)
+	})
+
+	t.Run("followed", func(t *testing.T) {
+		t.Parallel()
+
+		const appendedLog = sortTestLog +
+			`{"time":"1970-01-01T00:00:03.00","level":"INFO","message":"delta","duration":1}` + "\n" +
+			`{"time":"1970-01-01T00:00:00.50","level":"INFO","message":"omega","duration":1}` + "\n"
+
+		cfg := config.GetDefaultConfig()
+		setNotReversed(cfg)
+
+		inputSource, err := source.File(tests.RequireCreateFile(t, []byte(appendedLog)), cfg)
+		require.NoError(t, err)
+
+		t.Cleanup(func() { assert.NoError(t, inputSource.Close()) })
+
+		entries, err := inputSource.ParseLogEntries()
+		require.NoError(t, err)
+
+		initial := entries
+		initial.Entries = entries.Entries[:3]
+
+		model := handleUpdate(app.NewModel("-", cfg, testVersion), events.LogEntriesUpdateMsg(initial))
+		model = selectField(openSort(t, model), 1)
+		assertOrder(t, model.View(), "alpha", "gamma", "beta")
+
+		// Appended entries are merged into the sorted ones.
+		model = handleUpdate(model, events.LogEntriesUpdateMsg(entries))
+
+		view := model.View()
+		assert.Contains(t, view, "sort: Time asc")
+		assertOrder(t, view, "alpha", "omega", "gamma", "beta", "delta")
+	})
+
+	t.Run("back", func(t *testing.T) {
+		t.Parallel()
+
+		model := openSort(t, newTestModel(t, []byte(sortTestLog)))
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEsc})
+
+		_, ok := model.(app.StateLoadedModel)
+		require.Truef(t, ok, "%s", model)
+		assert.NotContains(t, model.View(), "sort:")
+	})
+}
+
+func TestViewSort(t *testing.T) {
+	t.Parallel()
+
+	model := newTestModelWithOptions(t, []byte(sortTestLog), []app.Option{app.WithView("slow")}, setNotReversed, func(cfg *config.Config) {
+		cfg.Views = []config.View{{
+			Name: "slow",
+			Sort: &config.Sort{Field: "$.duration", Descending: true},
+		}}
+	})
+
+	view := model.View()
+	assert.Contains(t, view, "sort: $.duration desc")
+	assertOrder(t, view, "gamma", "beta", "alpha")
+}
+
+func assertOrder(tb testing.TB, view string, messages ...string) {
+	tb.Helper()
+
+	for i := 1; i < len(messages); i++ {
+		assert.Less(tb, strings.Index(view, messages[i-1]), strings.Index(view, messages[i]), view)
+	}
+}
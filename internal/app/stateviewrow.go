@@ -1,7 +1,14 @@
 package app
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hedhyw/bubbles/key"
 
 	"github.com/hedhyw/json-log-viewer/internal/keymap"
 	"github.com/hedhyw/json-log-viewer/internal/pkg/events"
@@ -9,6 +16,9 @@ import (
 	"github.com/hedhyw/json-log-viewer/internal/pkg/widgets"
 )
 
+// maxCopyColumns is the number of columns that can be copied by digit keys.
+const maxCopyColumns = 9
+
 // StateViewRowModel is a state that shows extended JSON view.
 type StateViewRowModel struct {
 	*Application
@@ -20,6 +30,11 @@ type StateViewRowModel struct {
 	jsonView tea.Model
 
 	keys keymap.KeyMap
+
+	// copying is true if the next key selects what to copy.
+	copying bool
+	// status is a result of the last action.
+	status string
 }
 
 func newStateViewRow(
@@ -29,8 +44,8 @@ func newStateViewRow(
 	app := previousState.getApplication()
 
 	jsonViewModel, cmd := widgets.NewJSONViewModel(
-		logEntry.Line,
-		app.LastWindowSize(),
+		logEntry.Content(),
+		jsonViewSize(app.LastWindowSize()),
 		app.keys,
 	)
 
@@ -47,6 +62,14 @@ func newStateViewRow(
 	}
 }
 
+// jsonViewSize returns the size of the JSON view that leaves space for
+// the footer.
+func jsonViewSize(windowSize tea.WindowSizeMsg) tea.WindowSizeMsg {
+	windowSize.Height = max(windowSize.Height-footerSize, 1)
+
+	return windowSize
+}
+
 // Init initializes component. It implements tea.Model.
 func (s StateViewRowModel) Init() tea.Cmd {
 	return s.initCmd
@@ -54,7 +77,26 @@ func (s StateViewRowModel) Init() tea.Cmd {
 
 // View renders component. It implements tea.Model.
 func (s StateViewRowModel) View() string {
-	return s.jsonView.View()
+	return s.jsonView.View() + "\n" + s.FooterStyle.Render(s.viewFooter())
+}
+
+func (s StateViewRowModel) viewFooter() string {
+	switch {
+	case s.copying:
+		return s.viewCopyPrompt()
+	case s.status != "":
+		return s.status
+	default:
+		help := s.keys.Copy.Help().Key + " " + s.keys.Copy.Help().Desc + ", " +
+			s.keys.PrevEntry.Help().Key + " " + s.keys.PrevEntry.Help().Desc + ", " +
+			s.keys.NextEntry.Help().Key + " " + s.keys.NextEntry.Help().Desc
+
+		if note, ok := s.notes[s.logEntry.Index]; ok {
+			return "Note: " + note + " | " + help
+		}
+
+		return help
+	}
 }
 
 // Update handles events. It implements tea.Model.
@@ -63,8 +105,30 @@ func (s StateViewRowModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	s.Application.Update(msg)
 
-	if msg, ok := msg.(events.ErrorOccuredMsg); ok {
-		return s.handleErrorOccuredMsg(msg)
+	switch typedMsg := msg.(type) {
+	case events.ErrorOccuredMsg:
+		return s.handleErrorOccuredMsg(typedMsg)
+	case clipboardCopiedMsg:
+		return s.handleClipboardCopiedMsg(typedMsg), nil
+	case tea.WindowSizeMsg:
+		msg = jsonViewSize(typedMsg)
+	case tea.KeyMsg:
+		if s.copying {
+			return s.handleCopyKeyMsg(typedMsg)
+		}
+
+		switch {
+		case key.Matches(typedMsg, s.keys.Copy):
+			s.copying = true
+
+			return s, nil
+		case key.Matches(typedMsg, s.keys.PrevEntry):
+			return s.openNeighbour(-1)
+		case key.Matches(typedMsg, s.keys.NextEntry):
+			return s.openNeighbour(1)
+		}
+
+		s.status = ""
 	}
 
 	s.jsonView, cmd = s.jsonView.Update(msg)
@@ -85,6 +149,131 @@ func (s StateViewRowModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return s, cmd
 }
 
+// openNeighbour opens the entry that is next to the current one in the
+// table of the previous state, in the order in which the table shows them.
+// The entry is selected in the table, so it stays selected on return.
+func (s StateViewRowModel) openNeighbour(direction int) (tea.Model, tea.Cmd) {
+	previousState, ok := s.previousState.(tableStateModel)
+	if !ok {
+		return s, nil
+	}
+
+	table := previousState.getTable()
+	if table.lazyTable.reverse {
+		direction = -direction
+	}
+
+	cursor := table.Cursor() + direction
+	if cursor < 0 || cursor >= table.logEntries.Len() {
+		s.status = "No more entries"
+
+		return s, nil
+	}
+
+	table = table.Select(cursor)
+
+	return initializeModel(newStateViewRow(
+		table.logEntries.LogEntry(s.Config, cursor),
+		previousState.withTable(table),
+	))
+}
+
+// viewCopyPrompt lists keys that select what to copy.
+func (s StateViewRowModel) viewCopyPrompt() string {
+	options := make([]string, 0, 4)
+
+	for _, binding := range []key.Binding{s.keys.CopyLine, s.keys.CopyJSON, s.keys.CopyPath} {
+		if !binding.Enabled() {
+			continue
+		}
+
+		// Several keys are already listed in parentheses.
+		keys := binding.Help().Key
+		if !strings.HasPrefix(keys, "(") {
+			keys = "(" + keys + ")"
+		}
+
+		options = append(options, keys+" "+binding.Help().Desc)
+	}
+
+	columns := min(len(s.logEntry.Fields), maxCopyColumns)
+	options = append(options, fmt.Sprintf("(1-%d) column value", columns))
+
+	return "Copy: " + strings.Join(options, ", ")
+}
+
+// clipboardCopiedMsg is the result of copying a value to the clipboard.
+type clipboardCopiedMsg struct {
+	fallbackPath string
+	err          error
+}
+
+// handleCopyKeyMsg copies the value that is selected by the key. Other keys
+// cancel copying. The value is copied by the returned command, so the
+// sequence is not written while the view is rendered.
+func (s StateViewRowModel) handleCopyKeyMsg(msg tea.KeyMsg) (StateViewRowModel, tea.Cmd) {
+	s.copying = false
+	s.status = ""
+
+	var text string
+
+	switch {
+	case key.Matches(msg, s.keys.CopyLine):
+		text = string(bytes.TrimRight(s.logEntry.Line, "\r\n"))
+	case key.Matches(msg, s.keys.CopyJSON):
+		text = prettyJSON(s.logEntry.Content())
+	case key.Matches(msg, s.keys.CopyPath):
+		jsonView, ok := s.jsonView.(interface{ CursorPath() string })
+		if !ok {
+			s.status = "JSONPath of the selected node is not available"
+
+			return s, nil
+		}
+
+		text = jsonView.CursorPath()
+	default:
+		column, err := strconv.Atoi(msg.String())
+		if err != nil || column < 1 || column > min(len(s.logEntry.Fields), maxCopyColumns) {
+			return s, nil
+		}
+
+		text = s.logEntry.Fields[column-1]
+	}
+
+	clip := s.clipboard
+
+	return s, func() tea.Msg {
+		fallbackPath, err := clip.Copy(text)
+
+		return clipboardCopiedMsg{fallbackPath: fallbackPath, err: err}
+	}
+}
+
+// handleClipboardCopiedMsg shows the result of copying.
+func (s StateViewRowModel) handleClipboardCopiedMsg(msg clipboardCopiedMsg) StateViewRowModel {
+	switch {
+	case msg.err != nil:
+		s.status = "Copying failed: " + msg.err.Error()
+	case msg.fallbackPath != "":
+		s.status = "Clipboard is not supported, saved to " + msg.fallbackPath
+	default:
+		s.status = "Copied to clipboard"
+	}
+
+	return s
+}
+
+// prettyJSON indents the JSON content. A plain line is returned as is.
+func prettyJSON(content []byte) string {
+	var buf bytes.Buffer
+
+	if err := json.Indent(&buf, bytes.TrimSpace(content), "", "  "); err != nil {
+		return string(bytes.TrimRight(content, "\r\n"))
+	}
+
+	return buf.String()
+}
+
 // String implements fmt.Stringer.
 func (s StateViewRowModel) String() string {
 	return modelValue(s)
@@ -1,7 +1,12 @@
 package app_test
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -10,6 +15,8 @@ import (
 
 	"github.com/hedhyw/json-log-viewer/assets"
 	"github.com/hedhyw/json-log-viewer/internal/app"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/clipboard"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
 	"github.com/hedhyw/json-log-viewer/internal/pkg/events"
 )
 
@@ -92,3 +99,189 @@ func TestStateViewRow(t *testing.T) {
 		assert.Contains(t, model.View(), "message")
 	})
 }
+
+func TestStateViewRowCopy(t *testing.T) {
+	t.Parallel()
+
+	const jsonFile = `{"time":"1970-01-01T00:00:00.00","level":"INFO","message":"copied text"}` + "\n"
+
+	setup := func(t *testing.T, clip clipboard.Clipboard) tea.Model {
+		t.Helper()
+
+		model := newTestModelWithOptions(t, []byte(jsonFile), []app.Option{app.WithClipboard(clip)})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+
+		_, ok := model.(app.StateViewRowModel)
+		require.Truef(t, ok, "%s", model)
+
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+		assert.Contains(t, model.View(), "Copy:")
+
+		return model
+	}
+
+	testCases := [...]struct {
+		Name     string
+		Key      rune
+		Expected string
+	}{{
+		Name:     "line",
+		Key:      'l',
+		Expected: strings.TrimSpace(jsonFile),
+	}, {
+		Name:     "json",
+		Key:      'j',
+		Expected: "{\n  \"time\": \"1970-01-01T00:00:00.00\",\n  \"level\": \"INFO\",\n  \"message\": \"copied text\"\n}",
+	}, {
+		Name:     "column",
+		Key:      '3',
+		Expected: "copied text",
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			var output bytes.Buffer
+
+			model := setup(t, clipboard.Clipboard{
+				Output:      &output,
+				IsSupported: true,
+			})
+
+			model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{testCase.Key}})
+
+			assert.Contains(t, model.View(), "Copied to clipboard")
+			assert.Contains(t, output.String(), base64.StdEncoding.EncodeToString([]byte(testCase.Expected)))
+
+			_, ok := model.(app.StateViewRowModel)
+			assert.Truef(t, ok, "%s", model)
+		})
+	}
+
+	t.Run("fallback", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+
+		model := setup(t, clipboard.Clipboard{
+			FallbackDir: dir,
+		})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'3'}})
+
+		fallbackPaths, err := filepath.Glob(filepath.Join(dir, "jlv-clipboard-*.txt"))
+		require.NoError(t, err)
+		require.Len(t, fallbackPaths, 1)
+
+		fallbackPath := fallbackPaths[0]
+		assert.Contains(t, model.View(), fallbackPath)
+
+		content, err := os.ReadFile(fallbackPath)
+		require.NoError(t, err)
+
+		assert.Equal(t, "copied text", string(content))
+	})
+
+	t.Run("custom_keys", func(t *testing.T) {
+		t.Parallel()
+
+		var output bytes.Buffer
+
+		clip := clipboard.Clipboard{Output: &output, IsSupported: true}
+		model := newTestModelWithOptions(t, []byte(jsonFile), []app.Option{app.WithClipboard(clip)}, func(cfg *config.Config) {
+			cfg.Keys = map[string][]string{"copyLine": {"r"}, "copyPath": {}}
+		})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+
+		assert.Contains(t, model.View(), "Copy: (r) raw line, (j) JSON, (1-3) column value")
+
+		handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+		assert.Contains(t, output.String(), base64.StdEncoding.EncodeToString([]byte(strings.TrimSpace(jsonFile))))
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		t.Parallel()
+
+		var output bytes.Buffer
+
+		model := setup(t, clipboard.Clipboard{
+			Output:      &output,
+			IsSupported: true,
+		})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'9'}})
+
+		assert.NotContains(t, model.View(), "Copy:")
+		assert.Empty(t, output.String())
+
+		_, ok := model.(app.StateViewRowModel)
+		assert.Truef(t, ok, "%s", model)
+	})
+}
+
+func TestStateViewRowNeighbours(t *testing.T) {
+	t.Parallel()
+
+	const jsonFile = `{"time":"1970-01-01T00:00:00.00","level":"INFO","message":"first"}
+{"time":"1970-01-01T00:00:01.00","level":"ERROR","message":"second"}
+{"time":"1970-01-01T00:00:02.00","level":"INFO","message":"third"}
+`
+
+	prevEntry := tea.KeyMsg{Type: tea.KeyCtrlP}
+	nextEntry := tea.KeyMsg{Type: tea.KeyCtrlN}
+
+	t.Run("reverse", func(t *testing.T) {
+		t.Parallel()
+
+		// The newest entry is on the top by default.
+		model := newTestModel(t, []byte(jsonFile))
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+		assert.Contains(t, model.View(), `"third"`)
+
+		model = handleUpdate(model, prevEntry)
+		assert.Contains(t, model.View(), "No more entries")
+
+		model = handleUpdate(model, nextEntry)
+		assert.Contains(t, model.View(), `"second"`)
+
+		model = handleUpdate(model, nextEntry)
+		assert.Contains(t, model.View(), `"first"`)
+
+		_, ok := model.(app.StateViewRowModel)
+		require.Truef(t, ok, "%s", model)
+
+		// The entry stays selected in the table.
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEsc})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+		assert.Contains(t, model.View(), `"first"`)
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		t.Parallel()
+
+		model := newTestModel(t, []byte(jsonFile), func(cfg *config.Config) {
+			cfg.IsReverseDefault = false
+		})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+
+		for _, r := range "INFO" {
+			model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+		assert.Contains(t, model.View(), `"first"`)
+
+		// The filtered out entry is skipped.
+		model = handleUpdate(model, nextEntry)
+		assert.Contains(t, model.View(), `"third"`)
+
+		model = handleUpdate(model, prevEntry)
+		assert.Contains(t, model.View(), `"first"`)
+
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEsc})
+
+		_, ok := model.(app.StateFilteredModel)
+		require.Truef(t, ok, "%s", model)
+	})
+}
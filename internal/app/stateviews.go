@@ -0,0 +1,129 @@
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hedhyw/bubbles/key"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/events"
+)
+
+// defaultViewTitle is shown instead of the empty name of the default view.
+const defaultViewTitle = "(default)"
+
+// StateViewsModel is a state that lists views of the config to open one.
+type StateViewsModel struct {
+	*Application
+
+	loadedState   StateLoadedModel
+	previousState stateModel
+
+	// names of views, the first one is the default view.
+	names  []string
+	cursor int
+}
+
+func newStateViews(
+	loadedState StateLoadedModel,
+	previousState stateModel,
+) StateViewsModel {
+	app := previousState.getApplication()
+
+	names := make([]string, 0, len(app.baseConfig.Views)+1)
+	names = append(names, "")
+
+	cursor := 0
+
+	for _, view := range app.baseConfig.Views {
+		if view.Name == app.viewName {
+			cursor = len(names)
+		}
+
+		names = append(names, view.Name)
+	}
+
+	return StateViewsModel{
+		Application: app,
+
+		loadedState:   loadedState,
+		previousState: previousState,
+
+		names:  names,
+		cursor: cursor,
+	}
+}
+
+// Init initializes component. It implements tea.Model.
+func (s StateViewsModel) Init() tea.Cmd {
+	return nil
+}
+
+// View renders component. It implements tea.Model.
+func (s StateViewsModel) View() string {
+	var list strings.Builder
+
+	for i, name := range s.names {
+		if name == "" {
+			name = defaultViewTitle
+		}
+
+		if i == s.cursor {
+			list.WriteString("> " + name)
+		} else {
+			list.WriteString("  " + name)
+		}
+
+		if i < len(s.names)-1 {
+			list.WriteString("\n")
+		}
+	}
+
+	windowSize := s.LastWindowSize()
+	x, y := s.BaseStyle.GetFrameSize()
+
+	footer := s.FooterStyle.Render("Select a view: enter to open, esc to go back")
+
+	return s.BaseStyle.
+		Width(max(windowSize.Width-x, 1)).
+		Height(max(windowSize.Height-y-footerSize, 1)).
+		Render(list.String()) + "\n" + footer
+}
+
+// Update handles events. It implements tea.Model.
+func (s StateViewsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	s.Application.Update(msg)
+
+	switch msg := msg.(type) {
+	case events.ErrorOccuredMsg:
+		return s.handleErrorOccuredMsg(msg)
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, s.keys.Exit):
+			return s, tea.Quit
+		case key.Matches(msg, s.keys.Back):
+			return s.previousState.refresh()
+		case key.Matches(msg, s.keys.Up):
+			s.cursor = max(s.cursor-1, 0)
+		case key.Matches(msg, s.keys.Down):
+			s.cursor = min(s.cursor+1, len(s.names)-1)
+		case key.Matches(msg, s.keys.Open):
+			return s.loadedState.openView(s.names[s.cursor])
+		}
+	}
+
+	return s, nil
+}
+
+func (s StateViewsModel) getApplication() *Application {
+	return s.Application
+}
+
+func (s StateViewsModel) refresh() (_ stateModel, cmd tea.Cmd) {
+	return s, nil
+}
+
+// String implements fmt.Stringer.
+func (s StateViewsModel) String() string {
+	return modelValue(s)
+}
@@ -11,6 +11,12 @@ const (
 	footerPaddingLeft = 2
 )
 
+// markGutter is shown before the first cell of marked rows.
+const markGutter = "*"
+
+// notePrefix separates the note of the entry from its last column.
+const notePrefix = "  ✎ "
+
 // Possible colors.
 const (
 	colorMagenta lipgloss.Color = "13"
@@ -20,25 +26,21 @@ const (
 	colorRed     lipgloss.Color = "9"
 )
 
-func getTableStyles() table.Styles {
+func (t theme) tableStyles() table.Styles {
 	tableStyles := table.DefaultStyles()
-	tableStyles.Header = tableStyles.Header.
+	tableStyles.Header = t.header.apply(tableStyles.Header).
 		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("240")).
-		BorderBottom(true).
-		Bold(false)
-	tableStyles.Selected = tableStyles.Selected.
-		Foreground(lipgloss.Color("229")).
-		Background(lipgloss.Color("57")).
-		Bold(false)
+		BorderForeground(t.border).
+		BorderBottom(true)
+	tableStyles.Selected = t.selected.apply(tableStyles.Selected)
 
 	return tableStyles
 }
 
-func getBaseStyle() lipgloss.Style {
+func (t theme) baseStyle() lipgloss.Style {
 	return lipgloss.NewStyle().
 		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("240"))
+		BorderForeground(t.border)
 }
 
 func getFooterStyle() lipgloss.Style {
@@ -6,10 +6,11 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
 	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
 )
 
-func TestGetColorForLogLevel(t *testing.T) {
+func TestThemeLevelColor(t *testing.T) {
 	t.Parallel()
 
 	testCases := [...]struct {
@@ -51,8 +52,96 @@ func TestGetColorForLogLevel(t *testing.T) {
 		t.Run(testCase.Level.String(), func(t *testing.T) {
 			t.Parallel()
 
-			actual := getColorForLogLevel(testCase.Level)
+			actual := getDarkTheme().levelColor(testCase.Level)
 			assert.Equal(t, testCase.Expected, actual)
 		})
 	}
 }
+
+func TestGetTheme(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		actual := getTheme(config.GetDefaultConfig())
+		assert.Equal(t, getDarkTheme(), actual)
+	})
+
+	t.Run("light", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.GetDefaultConfig()
+		cfg.Theme.Name = config.ThemeLight
+
+		assert.Equal(t, getLightTheme(), getTheme(cfg))
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.GetDefaultConfig()
+		cfg.Theme = config.Theme{
+			Name:     config.ThemeHighContrast,
+			Levels:   map[string]string{"error": "#FF0000"},
+			Border:   "8",
+			Selected: config.ThemeStyle{Background: "21"},
+			JSON:     "1",
+		}
+
+		actual := getTheme(cfg)
+		assert.Equal(t, lipgloss.Color("#FF0000"), actual.levelColor(source.LevelError))
+		assert.Equal(t, getHighContrastTheme().levelColor(source.LevelInfo), actual.levelColor(source.LevelInfo))
+		assert.Equal(t, lipgloss.Color("8"), actual.border)
+		assert.Equal(t, themeStyle{foreground: "0", background: "21", bold: true}, actual.selected)
+		assert.Equal(t, "1", actual.json)
+
+		// The built-in theme is not changed.
+		assert.Equal(t, lipgloss.Color("196"), getHighContrastTheme().levelColor(source.LevelError))
+	})
+}
+
+// TestGetThemeNoColor is not parallel, because it sets the environment.
+func TestGetThemeNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	cfg := config.GetDefaultConfig()
+	cfg.Theme.Levels = map[string]string{"error": "#FF0000"}
+
+	actual := getTheme(cfg)
+	assert.Equal(t, getNoColorTheme(), actual)
+	assert.Empty(t, actual.levelColor(source.LevelError))
+	assert.Equal(t, "0", actual.json)
+}
+
+func TestApplyHighlightStyle(t *testing.T) {
+	t.Parallel()
+
+	style := lipgloss.NewStyle().Foreground(colorRed).Background(lipgloss.Color("57"))
+
+	actual := applyHighlightStyle(style, config.ThemeStyle{Background: "236", Bold: true})
+	assert.Equal(t, colorRed, actual.GetForeground())
+	assert.Equal(t, lipgloss.Color("236"), actual.GetBackground())
+	assert.True(t, actual.GetBold())
+
+	actual = applyHighlightStyle(style.Bold(true), config.ThemeStyle{Foreground: "#FF0000"})
+	assert.Equal(t, lipgloss.Color("#FF0000"), actual.GetForeground())
+	assert.True(t, actual.GetBold())
+}
+
+func TestGetThemeLevels(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	cfg.Levels = []config.Level{
+		{Name: "Notice", Color: "14"},
+		{Name: "error", Color: "1"},
+		{Name: "critical"},
+	}
+	cfg.Theme.Levels = map[string]string{"error": "9"}
+
+	actual := getTheme(cfg)
+	assert.Equal(t, lipgloss.Color("14"), actual.levelColor("notice"))
+	assert.Equal(t, lipgloss.Color("9"), actual.levelColor(source.LevelError))
+	assert.Empty(t, actual.levelColor("critical"))
+}
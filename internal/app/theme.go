@@ -0,0 +1,213 @@
+package app
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/widgets"
+)
+
+// theme contains colors and styles of the application.
+type theme struct {
+	levels   map[source.Level]lipgloss.Color
+	border   lipgloss.Color
+	header   themeStyle
+	selected themeStyle
+	footer   themeStyle
+	version  themeStyle
+	json     string
+}
+
+// themeStyle is a style of a part of the application.
+type themeStyle struct {
+	foreground lipgloss.Color
+	background lipgloss.Color
+	bold       bool
+	reverse    bool
+}
+
+// apply sets colors of the style, empty colors are not changed.
+func (s themeStyle) apply(style lipgloss.Style) lipgloss.Style {
+	if s.foreground != "" {
+		style = style.Foreground(s.foreground)
+	}
+
+	if s.background != "" {
+		style = style.Background(s.background)
+	}
+
+	return style.Bold(s.bold).Reverse(s.reverse)
+}
+
+// IDs of themes of the JSON viewer. The default theme is made for dark
+// backgrounds, light and high-contrast themes don't color JSON.
+const (
+	themeJSONDefault = "1"
+	themeJSONNoColor = "0"
+)
+
+func getDarkTheme() theme {
+	return theme{
+		levels: map[source.Level]lipgloss.Color{
+			source.LevelTrace:   colorMagenta,
+			source.LevelDebug:   colorYellow,
+			source.LevelInfo:    colorGreen,
+			source.LevelWarning: colorOrange,
+			source.LevelError:   colorRed,
+			source.LevelFatal:   colorRed,
+			source.LevelPanic:   colorRed,
+		},
+		border:   "240",
+		header:   themeStyle{},
+		selected: themeStyle{foreground: "229", background: "57"},
+		footer:   themeStyle{background: "#353533"},
+		version:  themeStyle{foreground: "#FFFDF5", background: "#6124DF"},
+		json:     themeJSONDefault,
+	}
+}
+
+func getLightTheme() theme {
+	return theme{
+		levels: map[source.Level]lipgloss.Color{
+			source.LevelTrace:   "90",
+			source.LevelDebug:   "130",
+			source.LevelInfo:    "28",
+			source.LevelWarning: "166",
+			source.LevelError:   "160",
+			source.LevelFatal:   "160",
+			source.LevelPanic:   "160",
+		},
+		border:   "245",
+		header:   themeStyle{},
+		selected: themeStyle{foreground: "232", background: "153"},
+		footer:   themeStyle{foreground: "#303030", background: "#E4E4E4"},
+		version:  themeStyle{foreground: "#FFFFFF", background: "#6124DF"},
+		json:     themeJSONNoColor,
+	}
+}
+
+func getHighContrastTheme() theme {
+	return theme{
+		levels: map[source.Level]lipgloss.Color{
+			source.LevelTrace:   "201",
+			source.LevelDebug:   "226",
+			source.LevelInfo:    "46",
+			source.LevelWarning: "208",
+			source.LevelError:   "196",
+			source.LevelFatal:   "196",
+			source.LevelPanic:   "196",
+		},
+		border:   "15",
+		header:   themeStyle{foreground: "15", bold: true},
+		selected: themeStyle{foreground: "0", background: "226", bold: true},
+		footer:   themeStyle{foreground: "15", background: "0"},
+		version:  themeStyle{foreground: "0", background: "15"},
+		json:     themeJSONNoColor,
+	}
+}
+
+// getNoColorTheme returns the theme without colors, the selected row and
+// bars are reversed, so they are still visible.
+func getNoColorTheme() theme {
+	return theme{
+		levels:   map[source.Level]lipgloss.Color{},
+		header:   themeStyle{bold: true},
+		selected: themeStyle{reverse: true},
+		footer:   themeStyle{reverse: true},
+		version:  themeStyle{reverse: true},
+		json:     themeJSONNoColor,
+	}
+}
+
+// getTheme returns the theme of the config. Colors are disabled if the
+// NO_COLOR environment variable is set, see https://no-color.org.
+func getTheme(cfg *config.Config) theme {
+	if os.Getenv("NO_COLOR") != "" {
+		return getNoColorTheme()
+	}
+
+	var t theme
+
+	switch cfg.Theme.Name {
+	case config.ThemeLight:
+		t = getLightTheme()
+	case config.ThemeHighContrast:
+		t = getHighContrastTheme()
+	default:
+		t = getDarkTheme()
+	}
+
+	for _, level := range cfg.Levels {
+		if level.Color != "" {
+			t.levels[source.Level(strings.ToLower(level.Name))] = lipgloss.Color(level.Color)
+		}
+	}
+
+	for level, color := range cfg.Theme.Levels {
+		t.levels[source.Level(strings.ToLower(level))] = lipgloss.Color(color)
+	}
+
+	if cfg.Theme.Border != "" {
+		t.border = lipgloss.Color(cfg.Theme.Border)
+	}
+
+	t.header = withThemeStyle(t.header, cfg.Theme.Header)
+	t.selected = withThemeStyle(t.selected, cfg.Theme.Selected)
+	t.footer = withThemeStyle(t.footer, cfg.Theme.Footer)
+
+	if cfg.Theme.JSON != "" {
+		t.json = cfg.Theme.JSON
+	}
+
+	return t
+}
+
+// withThemeStyle overrides the style by the style of the config.
+func withThemeStyle(style themeStyle, override config.ThemeStyle) themeStyle {
+	if override.Foreground != "" {
+		style.foreground = lipgloss.Color(override.Foreground)
+	}
+
+	if override.Background != "" {
+		style.background = lipgloss.Color(override.Background)
+	}
+
+	if override.Bold {
+		style.bold = true
+	}
+
+	return style
+}
+
+// applyHighlightStyle sets colors of the highlight, the style stays bold if
+// the highlight is not bold.
+func applyHighlightStyle(style lipgloss.Style, highlight config.ThemeStyle) lipgloss.Style {
+	if highlight.Foreground != "" {
+		style = style.Foreground(lipgloss.Color(highlight.Foreground))
+	}
+
+	if highlight.Background != "" {
+		style = style.Background(lipgloss.Color(highlight.Background))
+	}
+
+	if highlight.Bold {
+		style = style.Bold(true)
+	}
+
+	return style
+}
+
+// levelColor returns the color of the level, it is empty for unknown levels.
+func (t theme) levelColor(level source.Level) lipgloss.Color {
+	return t.levels[level]
+}
+
+// ApplyJSONTheme sets the theme of the JSON viewer from the config. The
+// theme is global, so it is applied once on start.
+func ApplyJSONTheme(cfg *config.Config) {
+	widgets.SetJSONTheme(getTheme(cfg).json)
+}
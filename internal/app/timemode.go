@@ -0,0 +1,157 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hedhyw/bubbles/table"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
+)
+
+// timeMode is the way times of time columns are shown.
+type timeMode int
+
+// Possible time modes.
+const (
+	// timeModeAbsolute shows formatted times.
+	timeModeAbsolute timeMode = iota
+	// timeModeRelative shows times relative to now, like "3m ago".
+	timeModeRelative
+	// timeModeDelta shows differences from times of previous entries.
+	timeModeDelta
+)
+
+// relativeTimeInterval is the period of updates of relative times.
+const relativeTimeInterval = time.Second
+
+// RelativeTimeTickMsg updates relative times of the table. Only the ticker
+// that is started last keeps ticking.
+type RelativeTimeTickMsg struct {
+	id int
+}
+
+// next returns the next mode, the modes are cycled.
+func (m timeMode) next() timeMode {
+	return (m + 1) % (timeModeDelta + 1)
+}
+
+// String implements fmt.Stringer.
+func (m timeMode) String() string {
+	switch m {
+	case timeModeRelative:
+		return "relative"
+	case timeModeDelta:
+		return "delta"
+	default:
+		return "absolute"
+	}
+}
+
+// tickRelativeTime starts the ticker of relative times if they are shown,
+// the previous ticker stops.
+func (m logsTableModel) tickRelativeTime() tea.Cmd {
+	if m.timeMode != timeModeRelative {
+		return nil
+	}
+
+	m.relativeTimeTicks++
+
+	return relativeTimeTick(m.relativeTimeTicks)
+}
+
+// handleRelativeTimeTickMsg renders relative times again and continues
+// ticking while the mode is on.
+func (m logsTableModel) handleRelativeTimeTickMsg(msg RelativeTimeTickMsg) (logsTableModel, tea.Cmd) {
+	if msg.id != m.relativeTimeTicks || m.timeMode != timeModeRelative {
+		return m, nil
+	}
+
+	m.lazyTable = m.lazyTable.RenderedTimes()
+
+	return m, relativeTimeTick(msg.id)
+}
+
+func relativeTimeTick(id int) tea.Cmd {
+	return tea.Tick(relativeTimeInterval, func(time.Time) tea.Msg {
+		return RelativeTimeTickMsg{id: id}
+	})
+}
+
+// timeRow replaces times of the row according to the time mode. The previous
+// entry is used in the delta mode, the time is kept if it is absent.
+func (m lazyTableModel) timeRow(
+	row table.Row,
+	entry source.LogEntry,
+	previous *source.LogEntry,
+) table.Row {
+	if m.timeMode == timeModeAbsolute {
+		return row
+	}
+
+	row = slices.Clone(row)
+
+	for i := range row {
+		at, ok := entry.Time(i, m.Config)
+		if !ok {
+			continue
+		}
+
+		switch m.timeMode {
+		case timeModeRelative:
+			row[i] = formatRelativeTime(time.Since(at))
+		case timeModeDelta:
+			if previous == nil {
+				continue
+			}
+
+			if previousAt, ok := previous.Time(i, m.Config); ok {
+				row[i] = formatTimeDelta(at.Sub(previousAt))
+			}
+		}
+	}
+
+	return row
+}
+
+// formatRelativeTime formats the duration from the time till now, like
+// "3m ago" or "in 5s".
+func formatRelativeTime(duration time.Duration) string {
+	format := "%s ago"
+
+	if duration < 0 {
+		format = "in %s"
+		duration = -duration
+	}
+
+	const day = 24 * time.Hour
+
+	var value string
+
+	switch {
+	case duration < time.Minute:
+		value = fmt.Sprintf("%ds", int(duration/time.Second))
+	case duration < time.Hour:
+		value = fmt.Sprintf("%dm", int(duration/time.Minute))
+	case duration < day:
+		value = fmt.Sprintf("%dh", int(duration/time.Hour))
+	default:
+		value = fmt.Sprintf("%dd", int(duration/day))
+	}
+
+	return fmt.Sprintf(format, value)
+}
+
+// formatTimeDelta formats the difference between times with a sign, like
+// "+1.5s".
+func formatTimeDelta(delta time.Duration) string {
+	delta = delta.Round(time.Millisecond)
+
+	if delta < 0 {
+		return delta.String()
+	}
+
+	return "+" + delta.String()
+}
@@ -0,0 +1,133 @@
+package app_test
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/app"
+)
+
+func TestStateLoadedTimeMode(t *testing.T) {
+	t.Parallel()
+
+	const jsonFile = `{"time":"2020-01-01T00:00:00Z","message":"first"}
+{"time":"2020-01-01T00:00:01.5Z","message":"second"}
+{"message":"third"}
+`
+
+	toggle := func(model tea.Model) tea.Model {
+		return handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	}
+
+	model := newTestModel(t, []byte(jsonFile), setNotReversed)
+	assert.Contains(t, model.View(), "2020-01-01T00:00:01Z")
+
+	model = toggle(model)
+
+	_, ok := model.(app.StateLoadedModel)
+	require.Truef(t, ok, "%s", model)
+
+	view := model.View()
+	assert.Contains(t, view, "d ago")
+	assert.NotContains(t, view, "2020-01-01T00:00:01Z")
+
+	model = toggle(model)
+
+	view = model.View()
+	// The first entry has no previous time, so its time is kept.
+	assert.Contains(t, view, "2020-01-01T00:00:00Z")
+	assert.Contains(t, view, "+1.5s")
+	assert.NotContains(t, view, "ago")
+
+	model = toggle(model)
+	assert.Contains(t, model.View(), "2020-01-01T00:00:01Z")
+}
+
+func TestStateLoadedRelativeTimeTick(t *testing.T) {
+	t.Parallel()
+
+	const jsonFile = `{"time":"2020-01-01T00:00:00Z","message":"first"}` + "\n"
+
+	toggle := func(model tea.Model) (tea.Model, tea.Cmd) {
+		return model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	}
+
+	model, cmd := toggle(newTestModel(t, []byte(jsonFile)))
+	tick := requireRelativeTimeTick(t, cmd)
+
+	model, cmd = model.Update(tick)
+	assert.NotNil(t, cmd, "the ticker continues")
+	assert.Contains(t, model.View(), "d ago")
+
+	// The ticker stops in other modes.
+	model, _ = toggle(model)
+	_, cmd = model.Update(tick)
+	assert.Nil(t, cmd)
+
+	// Only the last started ticker continues.
+	model, _ = toggle(model)
+	model, _ = toggle(model)
+	_, cmd = model.Update(tick)
+	assert.Nil(t, cmd)
+}
+
+// requireRelativeTimeTick runs commands until the tick of relative times.
+func requireRelativeTimeTick(tb testing.TB, cmd tea.Cmd) app.RelativeTimeTickMsg {
+	tb.Helper()
+
+	tick, ok := findRelativeTimeTick(cmd)
+	require.True(tb, ok)
+
+	return tick
+}
+
+func findRelativeTimeTick(cmd tea.Cmd) (app.RelativeTimeTickMsg, bool) {
+	if cmd == nil {
+		return app.RelativeTimeTickMsg{}, false
+	}
+
+	switch msg := cmd().(type) {
+	case app.RelativeTimeTickMsg:
+		return msg, true
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			if tick, ok := findRelativeTimeTick(cmd); ok {
+				return tick, true
+			}
+		}
+	}
+
+	return app.RelativeTimeTickMsg{}, false
+}
+
+func TestStateFilteredTimeMode(t *testing.T) {
+	t.Parallel()
+
+	const jsonFile = `{"time":"2020-01-01T00:00:00Z","message":"first"}
+{"time":"2020-01-01T00:00:02Z","message":"skipped"}
+{"time":"2020-01-01T00:01:00Z","message":"second"}
+`
+
+	model := newTestModel(t, []byte(jsonFile), setNotReversed)
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+
+	for _, r := range "/first|second/" {
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+
+	_, ok := model.(app.StateFilteredModel)
+	require.Truef(t, ok, "%s", model)
+
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+
+	view := model.View()
+	// Deltas are counted between the shown entries.
+	assert.Contains(t, view, "+1m0s")
+	assert.Contains(t, view, "time: delta")
+}
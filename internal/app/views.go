@@ -0,0 +1,125 @@
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/session"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
+)
+
+// WithView opens the view of the config after entries are loaded. It
+// replaces the filter of the previous session, so it must follow
+// WithSession.
+func WithView(name string) Option {
+	return func(app *Application) {
+		view, ok := app.baseConfig.View(name)
+		if !ok {
+			return
+		}
+
+		if app.restore == nil {
+			app.restore = &session.State{
+				Cursor:  -1,
+				Reverse: app.Config.IsReverseDefault,
+			}
+		}
+
+		app.restore.View = view.Name
+		app.restore.FilterText = view.Filter
+		app.restore.FilterField = view.FilterField
+		app.restore.MinLevel = view.MinLevel
+		app.restore.MarkedOnly = false
+
+		if view.Reverse != nil {
+			app.restore.Reverse = *view.Reverse
+		}
+	}
+}
+
+// useView switches columns and the sort order to the view. An empty name
+// switches back to the columns of the config and to the order of the log.
+// It returns false if the view is not found.
+func (app *Application) useView(name string) (config.View, bool) {
+	if name == "" {
+		app.useConfig(app.baseConfig)
+		app.viewName = ""
+		app.sort = config.Sort{}
+
+		return config.View{}, true
+	}
+
+	view, ok := app.baseConfig.View(name)
+	if !ok {
+		return config.View{}, false
+	}
+
+	app.useConfig(app.baseConfig.WithView(view))
+	app.viewName = view.Name
+	app.sort = config.Sort{}
+
+	if view.Sort != nil {
+		app.sort = *view.Sort
+	}
+
+	return view, true
+}
+
+// useFields replaces columns of the opened view or of the config. Columns
+// of the view are kept until the view is opened again.
+func (app *Application) useFields(fields []config.Field) {
+	cfg := *app.Config
+	cfg.Fields = fields
+	app.useConfig(&cfg)
+
+	if app.viewName == "" {
+		app.baseConfig = &cfg
+
+		return
+	}
+
+	baseConfig := *app.baseConfig
+	baseConfig.Views = make([]config.View, len(app.baseConfig.Views))
+	copy(baseConfig.Views, app.baseConfig.Views)
+
+	for i, view := range baseConfig.Views {
+		if view.Name == app.viewName {
+			baseConfig.Views[i].Fields = fields
+		}
+	}
+
+	app.baseConfig = &baseConfig
+}
+
+// useConfig replaces the config of the table. Highlight rules are prepared
+// again, because they refer to columns by their titles. Errors of the rules
+// don't depend on columns, they are shown on start.
+func (app *Application) useConfig(cfg *config.Config) {
+	app.Config = cfg
+	app.highlighter, _ = source.NewHighlighter(cfg.Highlights, cfg)
+}
+
+// openView applies columns, the order and the filter of the view.
+func (s StateLoadedModel) openView(name string) (tea.Model, tea.Cmd) {
+	view, ok := s.useView(name)
+	if !ok {
+		return s.refresh()
+	}
+
+	if view.Reverse != nil {
+		s.table.lazyTable.reverse = *view.Reverse
+	}
+
+	if view.Filter == "" && view.MinLevel == "" {
+		return s.refresh()
+	}
+
+	s, cmd := s.refreshTable()
+
+	filtered := newStateFiltered(s, view.Filter, view.FilterField, false)
+	filtered.minLevel = source.Level(strings.ToLower(view.MinLevel))
+
+	return filtered, tea.Batch(cmd, filtered.Init())
+}
@@ -0,0 +1,152 @@
+package app_test
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/app"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/session"
+)
+
+const viewsTestLog = `{"time":"1970-01-01T00:00:00.00","level":"INFO","message":"first","service":"api"}
+{"time":"1970-01-01T00:00:01.00","level":"ERROR","message":"second timeout","service":"db"}
+{"time":"1970-01-01T00:00:02.00","level":"WARN","message":"third timeout","service":"api"}
+`
+
+func setViews(cfg *config.Config) {
+	cfg.Views = []config.View{{
+		Name:     "errors",
+		Filter:   "timeout",
+		MinLevel: "error",
+	}, {
+		Name: "services",
+		Fields: []config.Field{{
+			Title:      "Service",
+			Kind:       config.FieldKindAny,
+			References: []string{"$.service"},
+		}},
+	}}
+}
+
+func TestStateViews(t *testing.T) {
+	t.Parallel()
+
+	openViews := func(t *testing.T, model tea.Model) tea.Model {
+		t.Helper()
+
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}})
+
+		_, ok := model.(app.StateViewsModel)
+		require.Truef(t, ok, "%s", model)
+
+		view := model.View()
+		assert.Contains(t, view, "(default)")
+		assert.Contains(t, view, "errors")
+		assert.Contains(t, view, "services")
+
+		return model
+	}
+
+	t.Run("filter", func(t *testing.T) {
+		t.Parallel()
+
+		model := openViews(t, newTestModel(t, []byte(viewsTestLog), setViews))
+		assert.Contains(t, model.View(), "> (default)")
+
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyDown})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+
+		_, ok := model.(app.StateFilteredModel)
+		require.Truef(t, ok, "%s", model)
+
+		view := model.View()
+		assert.Contains(t, view, "view errors: filtered 1 by: timeout, level: error+")
+		assert.Contains(t, view, "second")
+		assert.NotContains(t, view, "third")
+
+		state, ok := app.SessionState(model)
+		require.True(t, ok)
+		assert.Equal(t, "errors", state.View)
+		assert.Equal(t, "error", state.MinLevel)
+	})
+
+	t.Run("upper_case_level", func(t *testing.T) {
+		t.Parallel()
+
+		model := newTestModelWithOptions(t, []byte(viewsTestLog), []app.Option{app.WithView("errors")}, func(cfg *config.Config) {
+			cfg.Views = []config.View{{Name: "errors", MinLevel: "ERROR"}}
+		})
+
+		view := model.View()
+		assert.Contains(t, view, "level: error+")
+		assert.Contains(t, view, "second")
+		assert.NotContains(t, view, "third")
+	})
+
+	t.Run("columns", func(t *testing.T) {
+		t.Parallel()
+
+		model := openViews(t, newTestModel(t, []byte(viewsTestLog), setViews))
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyDown})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyDown})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+
+		_, ok := model.(app.StateLoadedModel)
+		require.Truef(t, ok, "%s", model)
+
+		view := model.View()
+		assert.Contains(t, view, "Service")
+		assert.NotContains(t, view, "Message")
+
+		// The default view restores columns.
+		model = openViews(t, model)
+		assert.Contains(t, model.View(), "> services")
+
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyUp})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyUp})
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEnter})
+		assert.Contains(t, model.View(), "Message")
+	})
+
+	t.Run("back", func(t *testing.T) {
+		t.Parallel()
+
+		model := openViews(t, newTestModel(t, []byte(viewsTestLog), setViews))
+		model = handleUpdate(model, tea.KeyMsg{Type: tea.KeyEsc})
+
+		_, ok := model.(app.StateLoadedModel)
+		require.Truef(t, ok, "%s", model)
+	})
+}
+
+func TestWithView(t *testing.T) {
+	t.Parallel()
+
+	options := []app.Option{
+		app.WithSession(session.State{FilterText: "first", Cursor: -1, Marks: []int{0}}),
+		app.WithView("errors"),
+	}
+
+	model := newTestModelWithOptions(t, []byte(viewsTestLog), options, setViews)
+
+	_, ok := model.(app.StateFilteredModel)
+	require.Truef(t, ok, "%s", model)
+	assert.Contains(t, model.View(), "view errors: filtered 1 by: timeout")
+
+	state, ok := app.SessionState(model)
+	require.True(t, ok)
+	assert.Equal(t, []int{0}, state.Marks)
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+
+		model := newTestModelWithOptions(t, []byte(viewsTestLog), []app.Option{app.WithView("unknown")}, setViews)
+
+		_, ok := model.(app.StateLoadedModel)
+		require.Truef(t, ok, "%s", model)
+	})
+}
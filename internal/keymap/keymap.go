@@ -1,6 +1,23 @@
 package keymap
 
-import "github.com/hedhyw/bubbles/key"
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hedhyw/bubbles/key"
+	"github.com/hedhyw/semerr/pkg/v1/semerr"
+)
+
+const (
+	// ErrUnknownAction is returned if the action of custom keys is unknown.
+	ErrUnknownAction semerr.Error = "unknown key action"
+	// ErrKeyConflict is returned if the key is bound to several actions.
+	ErrKeyConflict semerr.Error = "key conflict"
+	// ErrEmptyKey is returned if the name of the key is empty.
+	ErrEmptyKey semerr.Error = "empty key"
+)
 
 // KeyMap of the app.
 type KeyMap struct {
@@ -18,8 +35,63 @@ type KeyMap struct {
 	GotoTop         key.Binding
 	GotoBottom      key.Binding
 	ShowPreview     key.Binding
+	Export          key.Binding
+	Copy            key.Binding
+	Mark            key.Binding
+	NextMark        key.Binding
+	PrevMark        key.Binding
+	MarkedOnly      key.Binding
+	Annotate        key.Binding
+	Views           key.Binding
+	Columns         key.Binding
+	Sort            key.Binding
+	TimeMode        key.Binding
+	PrevEntry       key.Binding
+	NextEntry       key.Binding
+
+	// Keys of the column editor.
+	ColumnUp        key.Binding
+	ColumnDown      key.Binding
+	ColumnWider     key.Binding
+	ColumnNarrower  key.Binding
+	ColumnAutoWidth key.Binding
+	ColumnAdd       key.Binding
+	ColumnHide      key.Binding
+	ColumnSave      key.Binding
+
+	// Keys that select what to copy after Copy.
+	CopyLine key.Binding
+	CopyJSON key.Binding
+	CopyPath key.Binding
 }
 
+// keyScope is a set of actions that are handled by a single state, like
+// the column editor. Their keys may repeat keys of actions of other states.
+type keyScope struct {
+	// actions are handled only in the scope.
+	actions []string
+	// shared are actions of the table that are also handled in the scope.
+	shared []string
+	// reserved are fixed keys of the scope by their descriptions.
+	reserved map[string]string
+}
+
+// keyScopes are checked for conflicts separately from the table.
+var keyScopes = []keyScope{{
+	actions: []string{
+		"columnAdd", "columnAutoWidth", "columnDown", "columnHide",
+		"columnNarrower", "columnSave", "columnUp", "columnWider",
+	},
+	shared: []string{"back", "down", "exit", "open", "up"},
+}, {
+	actions: []string{"copyJSON", "copyLine", "copyPath"},
+	reserved: map[string]string{
+		"1": "copy column", "2": "copy column", "3": "copy column",
+		"4": "copy column", "5": "copy column", "6": "copy column",
+		"7": "copy column", "8": "copy column", "9": "copy column",
+	},
+}}
+
 // GetDefaultKeys returns default KeyMap.
 func GetDefaultKeys() KeyMap {
 	const spacebar = " "
@@ -75,9 +147,240 @@ func GetDefaultKeys() KeyMap {
 			key.WithKeys("end", "G"),
 			key.WithHelp("(end, G)", "go to end"),
 		),
+		Export: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "Export"),
+		),
+		Copy: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "Copy entry"),
+		),
+		Mark: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "Mark"),
+		),
+		NextMark: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "next mark"),
+		),
+		PrevMark: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "previous mark"),
+		),
+		MarkedOnly: key.NewBinding(
+			key.WithKeys("M"),
+			key.WithHelp("M", "Marked only"),
+		),
+		Annotate: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "Note"),
+		),
+		Views: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "Views"),
+		),
+		Columns: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "Columns"),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "Sort"),
+		),
+		TimeMode: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "Time: relative/delta"),
+		),
+		PrevEntry: key.NewBinding(
+			key.WithKeys("ctrl+p", "shift+up"),
+			key.WithHelp("ctrl+p", "previous entry"),
+		),
+		NextEntry: key.NewBinding(
+			key.WithKeys("ctrl+n", "shift+down"),
+			key.WithHelp("ctrl+n", "next entry"),
+		),
+		ShowPreview: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "Preview"),
+		),
+		ColumnUp: key.NewBinding(
+			key.WithKeys("K", "shift+up"),
+			key.WithHelp("K", "move up"),
+		),
+		ColumnDown: key.NewBinding(
+			key.WithKeys("J", "shift+down"),
+			key.WithHelp("J", "move down"),
+		),
+		ColumnWider: key.NewBinding(
+			key.WithKeys("+", "=", "right"),
+			key.WithHelp("+", "wider"),
+		),
+		ColumnNarrower: key.NewBinding(
+			key.WithKeys("-", "left"),
+			key.WithHelp("-", "narrower"),
+		),
+		ColumnAutoWidth: key.NewBinding(
+			key.WithKeys("0"),
+			key.WithHelp("0", "auto width"),
+		),
+		ColumnAdd: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "add"),
+		),
+		ColumnHide: key.NewBinding(
+			key.WithKeys("d", "delete"),
+			key.WithHelp("d", "hide"),
+		),
+		ColumnSave: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "save"),
+		),
+		CopyLine: key.NewBinding(
+			key.WithKeys("l"),
+			key.WithHelp("l", "raw line"),
+		),
+		CopyJSON: key.NewBinding(
+			key.WithKeys("j"),
+			key.WithHelp("j", "JSON"),
+		),
+		CopyPath: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "JSONPath"),
+		),
 	}
 }
 
+// New returns the default KeyMap where keys of actions are replaced by the
+// custom keys, see ActionNames. An empty list of keys disables the action.
+// It fails if a key is bound to several actions of the same scope.
+func New(custom map[string][]string) (KeyMap, error) {
+	keyMap := GetDefaultKeys()
+	actions := keyMap.actions()
+
+	for _, name := range slices.Sorted(maps.Keys(custom)) {
+		binding, ok := actions[name]
+		if !ok {
+			return KeyMap{}, fmt.Errorf("%w: %q, known actions: %s",
+				ErrUnknownAction, name, strings.Join(ActionNames(), ", "))
+		}
+
+		keys := custom[name]
+		if len(keys) == 0 {
+			binding.Unbind()
+
+			continue
+		}
+
+		if slices.Contains(keys, "") {
+			return KeyMap{}, fmt.Errorf("%w: %s", ErrEmptyKey, name)
+		}
+
+		binding.SetKeys(keys...)
+		binding.SetHelp(helpKey(keys), binding.Help().Desc)
+	}
+
+	tableActions := ActionNames()
+
+	for _, scope := range keyScopes {
+		tableActions = slices.DeleteFunc(tableActions, func(name string) bool {
+			return slices.Contains(scope.actions, name)
+		})
+
+		err := checkConflicts(actions, append(slices.Clone(scope.shared), scope.actions...), scope.reserved)
+		if err != nil {
+			return KeyMap{}, err
+		}
+	}
+
+	if err := checkConflicts(actions, tableActions, nil); err != nil {
+		return KeyMap{}, err
+	}
+
+	return keyMap, nil
+}
+
+// checkConflicts fails if a key is bound to several of the actions or to
+// an action and a reserved key.
+func checkConflicts(actions map[string]*key.Binding, names []string, reserved map[string]string) error {
+	boundTo := make(map[string]string, len(reserved))
+	maps.Copy(boundTo, reserved)
+
+	for _, name := range slices.Sorted(slices.Values(names)) {
+		for _, k := range actions[name].Keys() {
+			if other, ok := boundTo[k]; ok {
+				return fmt.Errorf("%w: %q is bound to %s and %s",
+					ErrKeyConflict, k, other, name)
+			}
+
+			boundTo[k] = name
+		}
+	}
+
+	return nil
+}
+
+// ActionNames returns sorted names of actions that can be bound to custom
+// keys in the config.
+func ActionNames() []string {
+	keyMap := GetDefaultKeys()
+
+	return slices.Sorted(maps.Keys(keyMap.actions()))
+}
+
+// actions returns bindings by names of their actions in the config.
+func (k *KeyMap) actions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"exit":       &k.Exit,
+		"back":       &k.Back,
+		"open":       &k.Open,
+		"up":         &k.Up,
+		"down":       &k.Down,
+		"reverse":    &k.Reverse,
+		"pageUp":     &k.PageUp,
+		"pageDown":   &k.PageDown,
+		"filter":     &k.Filter,
+		"help":       &k.ToggleFullHelp,
+		"gotoTop":    &k.GotoTop,
+		"gotoBottom": &k.GotoBottom,
+		"preview":    &k.ShowPreview,
+		"export":     &k.Export,
+		"copy":       &k.Copy,
+		"mark":       &k.Mark,
+		"nextMark":   &k.NextMark,
+		"prevMark":   &k.PrevMark,
+		"markedOnly": &k.MarkedOnly,
+		"annotate":   &k.Annotate,
+		"views":      &k.Views,
+		"columns":    &k.Columns,
+		"sort":       &k.Sort,
+		"time":       &k.TimeMode,
+		"prevEntry":  &k.PrevEntry,
+		"nextEntry":  &k.NextEntry,
+
+		"columnUp":        &k.ColumnUp,
+		"columnDown":      &k.ColumnDown,
+		"columnWider":     &k.ColumnWider,
+		"columnNarrower":  &k.ColumnNarrower,
+		"columnAutoWidth": &k.ColumnAutoWidth,
+		"columnAdd":       &k.ColumnAdd,
+		"columnHide":      &k.ColumnHide,
+		"columnSave":      &k.ColumnSave,
+
+		"copyLine": &k.CopyLine,
+		"copyJSON": &k.CopyJSON,
+		"copyPath": &k.CopyPath,
+	}
+}
+
+// helpKey formats keys for the help like the default keys.
+func helpKey(keys []string) string {
+	if len(keys) == 1 {
+		return keys[0]
+	}
+
+	return "(" + strings.Join(keys, ", ") + ")"
+}
+
 func (k KeyMap) ShortHelp() []key.Binding {
 	return []key.Binding{
 		k.Back, k.Open, k.Up, k.Down, k.PageUp, k.PageDown, k.ToggleFullHelp,
@@ -89,8 +392,13 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 		{k.Up, k.Down},
 		{k.Back, k.Open},
 		{k.Filter, k.Reverse},
+		{k.Views, k.Columns},
 		{k.PageUp, k.PageDown},
 		{k.GotoTop, k.GotoBottom},
+		{k.Mark, k.MarkedOnly},
+		{k.PrevMark, k.NextMark},
+		{k.Export, k.Annotate},
+		{k.Sort, k.ShowPreview, k.TimeMode},
 		{k.ToggleFullHelp, k.Exit},
 	}
 }
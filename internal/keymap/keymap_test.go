@@ -0,0 +1,114 @@
+package keymap_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/keymap"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		keys, err := keymap.New(nil)
+		require.NoError(t, err)
+		assert.Equal(t, keymap.GetDefaultKeys(), keys)
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		t.Parallel()
+
+		keys, err := keymap.New(map[string][]string{
+			"filter":   {"/"},
+			"pageDown": {"pgdown", "ctrl+d"},
+			"preview":  {},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"/"}, keys.Filter.Keys())
+		assert.Equal(t, "/", keys.Filter.Help().Key)
+		assert.Equal(t, "Filter", keys.Filter.Help().Desc)
+		assert.Equal(t, "(pgdown, ctrl+d)", keys.PageDown.Help().Key)
+		assert.False(t, keys.ShowPreview.Enabled())
+	})
+
+	t.Run("moved", func(t *testing.T) {
+		t.Parallel()
+
+		// The key of another action can be reused if that action is moved.
+		keys, err := keymap.New(map[string][]string{
+			"filter":  {"r"},
+			"reverse": {"R"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"r"}, keys.Filter.Keys())
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := keymap.New(map[string][]string{"filter": {"r"}})
+		require.ErrorIs(t, err, keymap.ErrKeyConflict)
+		assert.ErrorContains(t, err, "filter and reverse")
+	})
+
+	t.Run("scoped", func(t *testing.T) {
+		t.Parallel()
+
+		// Keys of the column editor may repeat keys of the table.
+		keys, err := keymap.New(map[string][]string{"columnHide": {"f"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"f"}, keys.ColumnHide.Keys())
+	})
+
+	t.Run("scoped_conflict", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := [...]struct {
+			Name     string
+			Custom   map[string][]string
+			Expected string
+		}{{
+			Name:     "scope",
+			Custom:   map[string][]string{"columnHide": {"a"}},
+			Expected: "columnAdd and columnHide",
+		}, {
+			Name:     "shared",
+			Custom:   map[string][]string{"columnHide": {"q"}},
+			Expected: "back and columnHide",
+		}, {
+			Name:     "reserved",
+			Custom:   map[string][]string{"copyLine": {"1"}},
+			Expected: "copy column and copyLine",
+		}}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.Name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := keymap.New(testCase.Custom)
+				require.ErrorIs(t, err, keymap.ErrKeyConflict)
+				assert.ErrorContains(t, err, testCase.Expected)
+			})
+		}
+	})
+
+	t.Run("unknown_action", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := keymap.New(map[string][]string{"unknown": {"x"}})
+		require.ErrorIs(t, err, keymap.ErrUnknownAction)
+	})
+
+	t.Run("empty_key", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := keymap.New(map[string][]string{"filter": {""}})
+		require.ErrorIs(t, err, keymap.ErrEmptyKey)
+	})
+}
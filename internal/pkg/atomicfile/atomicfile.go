@@ -0,0 +1,52 @@
+// Package atomicfile replaces files atomically, so a file is not broken if
+// the writing fails.
+package atomicfile
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WriteFile writes the content to a temporary file next to the target and
+// renames it to the target. If the path is a symlink, the file it points to
+// is replaced and the symlink is kept. An existing file keeps its
+// permissions, a new file is created with perm.
+func WriteFile(path string, content []byte, perm fs.FileMode) error {
+	target, err := filepath.EvalSymlinks(path)
+
+	switch {
+	case err == nil:
+		info, err := os.Stat(target)
+		if err != nil {
+			return fmt.Errorf("stat: %w", err)
+		}
+
+		perm = info.Mode().Perm()
+	case errors.Is(err, fs.ErrNotExist):
+		// A dangling symlink is replaced by the file.
+		target = path
+	default:
+		return fmt.Errorf("resolving symlinks: %w", err)
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(target), filepath.Base(target)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %w", err)
+	}
+
+	_, err = tempFile.Write(content)
+	err = errors.Join(err, tempFile.Chmod(perm), tempFile.Close())
+
+	if err == nil {
+		err = os.Rename(tempFile.Name(), target)
+	}
+
+	if err != nil {
+		return errors.Join(fmt.Errorf("writing: %w", err), os.Remove(tempFile.Name()))
+	}
+
+	return nil
+}
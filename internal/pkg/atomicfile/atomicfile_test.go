@@ -0,0 +1,78 @@
+package atomicfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/atomicfile"
+)
+
+func TestWriteFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("new_file", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "file.json")
+
+		require.NoError(t, atomicfile.WriteFile(path, []byte("new"), 0o640))
+
+		assertFile(t, path, "new", 0o640)
+	})
+
+	t.Run("existing_file", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "file.json")
+		require.NoError(t, os.WriteFile(path, []byte("old"), 0o600))
+		require.NoError(t, os.Chmod(path, 0o664))
+
+		require.NoError(t, atomicfile.WriteFile(path, []byte("new"), 0o600))
+
+		// The permissions of the file are kept.
+		assertFile(t, path, "new", 0o664)
+	})
+
+	t.Run("symlink", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		target := filepath.Join(dir, "target.json")
+		path := filepath.Join(dir, "link.json")
+
+		require.NoError(t, os.WriteFile(target, []byte("old"), 0o600))
+		require.NoError(t, os.Symlink(target, path))
+
+		require.NoError(t, atomicfile.WriteFile(path, []byte("new"), 0o644))
+
+		info, err := os.Lstat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.ModeSymlink, info.Mode().Type())
+
+		assertFile(t, target, "new", 0o600)
+	})
+
+	t.Run("directory_not_found", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "not_found", "file.json")
+
+		require.ErrorIs(t, atomicfile.WriteFile(path, []byte("new"), 0o600), os.ErrNotExist)
+	})
+}
+
+func assertFile(tb testing.TB, path string, expected string, perm os.FileMode) {
+	tb.Helper()
+
+	content, err := os.ReadFile(path)
+	require.NoError(tb, err)
+	assert.Equal(tb, expected, string(content))
+
+	info, err := os.Stat(path)
+	require.NoError(tb, err)
+	assert.Equal(tb, perm, info.Mode().Perm())
+}
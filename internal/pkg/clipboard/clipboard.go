@@ -0,0 +1,127 @@
+// Package clipboard copies text to the system clipboard through the terminal.
+package clipboard
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	"github.com/mattn/go-isatty"
+)
+
+// fallbackFilePattern is a pattern of the file name that receives
+// the copied text if the terminal doesn't support OSC 52.
+const fallbackFilePattern = "jlv-clipboard-*.txt"
+
+// terminalPath is the controlling terminal of the process.
+const terminalPath = "/dev/tty"
+
+// Clipboard copies text using the OSC 52 escape sequence. The terminal
+// emulator sets the clipboard itself, so it also works over SSH.
+type Clipboard struct {
+	// Output of the terminal. The controlling terminal is opened for
+	// each copy if it is nil.
+	Output io.Writer
+	// IsSupported is false if the terminal can't handle the sequence.
+	IsSupported bool
+	// Tmux and Screen wrap the sequence so that it is passed through
+	// the terminal multiplexer.
+	Tmux   bool
+	Screen bool
+	// FallbackDir is the directory where a new file receives the text if
+	// the sequence is not supported. The temporary directory is used if it
+	// is empty.
+	FallbackDir string
+}
+
+// New returns a clipboard of the controlling terminal. The sequence is
+// written to the terminal directly, so it doesn't pass through the
+// standard output that the program renders to.
+func New() Clipboard {
+	term := os.Getenv("TERM")
+
+	return Clipboard{
+		IsSupported: isTerminal() &&
+			// The virtual console of Linux and dumb terminals ignore it.
+			term != "dumb" && term != "linux",
+		Tmux:   os.Getenv("TMUX") != "",
+		Screen: os.Getenv("TMUX") == "" && strings.HasPrefix(term, "screen"),
+	}
+}
+
+// Copy puts the text to the clipboard. If the terminal doesn't support it,
+// the text is written to a new fallback file and its path is returned.
+func (c Clipboard) Copy(text string) (fallbackPath string, err error) {
+	if !c.IsSupported {
+		return c.writeFallback(text)
+	}
+
+	seq := osc52.New(text)
+
+	switch {
+	case c.Tmux:
+		seq = seq.Tmux()
+	case c.Screen:
+		seq = seq.Screen()
+	}
+
+	if c.Output != nil {
+		if _, err := seq.WriteTo(c.Output); err != nil {
+			return "", fmt.Errorf("writing sequence: %w", err)
+		}
+
+		return "", nil
+	}
+
+	terminal, err := os.OpenFile(terminalPath, os.O_WRONLY, 0)
+	if err != nil {
+		return "", fmt.Errorf("opening terminal: %w", err)
+	}
+
+	_, err = seq.WriteTo(terminal)
+	if errClose := terminal.Close(); err == nil {
+		err = errClose
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("writing sequence: %w", err)
+	}
+
+	return "", nil
+}
+
+// isTerminal returns true if the controlling terminal can be opened.
+func isTerminal() bool {
+	terminal, err := os.OpenFile(terminalPath, os.O_WRONLY, 0)
+	if err != nil {
+		return false
+	}
+
+	defer terminal.Close()
+
+	return isatty.IsTerminal(terminal.Fd())
+}
+
+// writeFallback writes the text to a new file. The file is created
+// exclusively with a random name, so a file or a symlink that another user
+// has planted in the shared temporary directory is never written to.
+func (c Clipboard) writeFallback(text string) (string, error) {
+	file, err := os.CreateTemp(c.FallbackDir, fallbackFilePattern)
+	if err != nil {
+		return "", fmt.Errorf("creating fallback file: %w", err)
+	}
+
+	_, err = file.WriteString(text)
+	if errClose := file.Close(); err == nil {
+		err = errClose
+	}
+
+	if err != nil {
+		return "", errors.Join(fmt.Errorf("writing fallback file: %w", err), os.Remove(file.Name()))
+	}
+
+	return file.Name(), nil
+}
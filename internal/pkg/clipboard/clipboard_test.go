@@ -0,0 +1,119 @@
+package clipboard_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/clipboard"
+)
+
+func TestCopy(t *testing.T) {
+	t.Parallel()
+
+	const text = "trace-id"
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(text))
+
+	testCases := [...]struct {
+		Name     string
+		Tmux     bool
+		Screen   bool
+		Expected string
+	}{{
+		Name:     "terminal",
+		Expected: "\x1b]52;c;" + encoded + "\x07",
+	}, {
+		Name:     "tmux",
+		Tmux:     true,
+		Expected: "\x1bPtmux;\x1b\x1b]52;c;" + encoded + "\x07\x1b\\",
+	}, {
+		Name:     "screen",
+		Screen:   true,
+		Expected: "\x1bP\x1b]52;c;" + encoded + "\x07\x1b\\",
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			var output bytes.Buffer
+
+			fallbackPath, err := clipboard.Clipboard{
+				Output:      &output,
+				IsSupported: true,
+				Tmux:        testCase.Tmux,
+				Screen:      testCase.Screen,
+			}.Copy(text)
+			require.NoError(t, err)
+
+			assert.Empty(t, fallbackPath)
+			assert.Equal(t, testCase.Expected, output.String())
+		})
+	}
+}
+
+func TestCopyFallback(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+
+		fallbackPath, err := clipboard.Clipboard{FallbackDir: dir}.Copy(t.Name())
+		require.NoError(t, err)
+
+		assert.Equal(t, dir, filepath.Dir(fallbackPath))
+
+		content, err := os.ReadFile(fallbackPath)
+		require.NoError(t, err)
+
+		assert.Equal(t, t.Name(), string(content))
+
+		info, err := os.Stat(fallbackPath)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	})
+
+	t.Run("new_file", func(t *testing.T) {
+		t.Parallel()
+
+		c := clipboard.Clipboard{FallbackDir: t.TempDir()}
+
+		first, err := c.Copy("first")
+		require.NoError(t, err)
+
+		second, err := c.Copy("second")
+		require.NoError(t, err)
+
+		assert.NotEqual(t, first, second)
+
+		content, err := os.ReadFile(first)
+		require.NoError(t, err)
+		assert.Equal(t, "first", string(content))
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := clipboard.Clipboard{
+			FallbackDir: filepath.Join(t.TempDir(), "not_found"),
+		}.Copy(t.Name())
+		require.Error(t, err)
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Setenv("TERM", "dumb")
+
+	c := clipboard.New()
+
+	assert.False(t, c.IsSupported)
+	assert.Nil(t, c.Output)
+}
@@ -1,16 +1,26 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	units "github.com/docker/go-units"
 	"github.com/go-playground/validator/v10"
 	"github.com/hedhyw/jsoncjson"
+
+	"github.com/hedhyw/json-log-viewer/internal/keymap"
 )
 
 // DefaultTimeFormat is a time format used in formatting timestamps by default.
@@ -24,6 +34,10 @@ type Config struct {
 	// Path to the config.
 	Path string `json:"-"`
 
+	// Extends is the name of the built-in preset that the config is based
+	// on, see PresetNames.
+	Extends string `json:"extends,omitempty"`
+
 	Fields []Field `json:"fields" validate:"min=1"`
 
 	TimeLayoutsDeprecated []string `json:"time_layouts,omitempty"`
@@ -32,10 +46,238 @@ type Config struct {
 
 	IsReverseDefault bool `json:"isReverseDefault"`
 
+	// TimeZone of formatted times: "local", "UTC" or an IANA name, like
+	// "Europe/Berlin". Epoch times are formatted in UTC and parsed times keep
+	// their zones by default.
+	TimeZone string `json:"timeZone,omitempty"`
+
 	CustomLevelMapping map[string]string `json:"customLevelMapping"`
 
+	// Levels replace built-in levels, they are ordered from the least
+	// severe. Values of CustomLevelMapping are names of these levels.
+	Levels []Level `json:"levels,omitempty" validate:"unique=Name,dive"`
+
 	// MaxFileSizeBytes is the maximum size of the file to load.
 	MaxFileSizeBytes ByteSize `json:"maxFileSizeBytes" validate:"min=1"`
+
+	// Multiline describes how the lines of a single entry are joined.
+	Multiline Multiline `json:"multiline,omitzero"`
+
+	// Unwrap lists fields that hold JSON encoded as a string. They are
+	// decoded before the fields are rendered.
+	Unwrap []Unwrap `json:"unwrap,omitempty" validate:"dive"`
+
+	// InputFormat of the log. By default, each line is an entry.
+	InputFormat InputFormat `json:"inputFormat,omitempty" validate:"omitempty,oneof=lines json csv tsv"`
+
+	// Views are named presets of the filter and columns.
+	Views []View `json:"views,omitempty" validate:"unique=Name,dive"`
+
+	// Profiles are columns for logs of a specific shape, they are selected
+	// automatically by their match rules.
+	Profiles []Profile `json:"profiles,omitempty" validate:"unique=Name,dive"`
+
+	// Highlights style rows or cells of entries that match their rules.
+	Highlights []Highlight `json:"highlights,omitempty" validate:"dive"`
+
+	// Theme customizes colors.
+	Theme Theme `json:"theme,omitzero"`
+
+	// Keys replace keys of actions by their names, for example,
+	// {"filter": ["/"]}. See keymap.ActionNames.
+	Keys map[string][]string `json:"keys,omitempty"`
+
+	// Profile is the name of the selected profile, it is empty if the columns
+	// are not taken from a profile.
+	Profile string `json:"-"`
+
+	// profileConfigs are configs of profiles by their names. They are
+	// created once the config is read, because entries are parsed with
+	// them many times.
+	profileConfigs map[string]*Config
+}
+
+// Profile is a named set of columns for logs of a specific shape.
+type Profile struct {
+	Name  string       `json:"name" validate:"required"`
+	Match ProfileMatch `json:"match"`
+	// Fields replace columns of the config.
+	Fields []Field `json:"fields" validate:"min=1,dive"`
+	// CustomLevelMapping is merged with the mapping of the config when the
+	// config is read.
+	CustomLevelMapping map[string]string `json:"customLevelMapping,omitempty"`
+}
+
+// ProfileMatch describes logs of the profile. At least one rule is required,
+// a log matches if any of them matches.
+type ProfileMatch struct {
+	// Files are glob patterns of names or paths of log files, for example,
+	// "nginx-*.log".
+	Files []string `json:"files,omitempty" validate:"required_without=Keys"`
+	// Keys are JSON paths that all exist in every entry of the log, for
+	// example, "$.severity".
+	Keys []string `json:"keys,omitempty" validate:"required_without=Files,dive,required"`
+}
+
+// ProfileForFile returns the first profile that matches the file name by
+// the glob pattern.
+func (c *Config) ProfileForFile(fileName string) (Profile, bool) {
+	for _, profile := range c.Profiles {
+		for _, pattern := range profile.Match.Files {
+			if matchGlob(pattern, fileName) || matchGlob(pattern, filepath.Base(fileName)) {
+				return profile, true
+			}
+		}
+	}
+
+	return Profile{}, false
+}
+
+func matchGlob(pattern string, name string) bool {
+	matched, err := filepath.Match(pattern, name)
+
+	return err == nil && matched
+}
+
+// WithProfile returns a copy of the config with columns of the profile.
+func (c *Config) WithProfile(profile Profile) *Config {
+	cfg := *c
+
+	cfg.Profile = profile.Name
+	cfg.Fields = profile.Fields
+
+	if profile.CustomLevelMapping != nil {
+		cfg.CustomLevelMapping = profile.CustomLevelMapping
+	}
+
+	return &cfg
+}
+
+// ProfileConfig returns the config that parses entries of the profile.
+func (c *Config) ProfileConfig(profile Profile) *Config {
+	if cfg, ok := c.profileConfigs[profile.Name]; ok {
+		return cfg
+	}
+
+	return c.WithProfile(profile)
+}
+
+// resolveProfiles creates configs of profiles.
+func (c *Config) resolveProfiles() {
+	c.profileConfigs = make(map[string]*Config, len(c.Profiles))
+
+	for _, profile := range c.Profiles {
+		c.profileConfigs[profile.Name] = c.WithProfile(profile)
+	}
+}
+
+// View is a named preset of the table, for example, for triaging errors.
+type View struct {
+	Name string `json:"name" validate:"required"`
+	// Filter is a term to filter entries by. It has the same syntax as the
+	// interactive filter.
+	Filter string `json:"filter,omitempty"`
+	// FilterField is the title of the column to filter by. All columns are
+	// searched by default.
+	FilterField string `json:"filterField,omitempty"`
+	// MinLevel hides entries with less severe levels and without a level.
+	MinLevel string `json:"minLevel,omitempty"`
+	// Fields replace columns of the config if they are set.
+	Fields []Field `json:"fields,omitempty" validate:"omitempty,dive"`
+	// Reverse overrides isReverseDefault if it is set.
+	Reverse *bool `json:"reverse,omitempty"`
+	// Sort orders entries, they are shown in the order of the log by default.
+	Sort *Sort `json:"sort,omitempty"`
+}
+
+// Sort orders entries by a column or by a value of the JSON.
+type Sort struct {
+	// Field is the title of a column or a JSONPath, like "$.duration".
+	Field string `json:"field" validate:"required"`
+	// Descending orders entries from the greatest value.
+	Descending bool `json:"descending,omitempty"`
+}
+
+// IsZero returns true if entries are not sorted.
+func (s Sort) IsZero() bool {
+	return s.Field == ""
+}
+
+// String returns a short description of the sort, like "Time desc".
+func (s Sort) String() string {
+	if s.Descending {
+		return s.Field + " desc"
+	}
+
+	return s.Field + " asc"
+}
+
+// View returns the view by its name.
+func (c *Config) View(name string) (View, bool) {
+	for _, view := range c.Views {
+		if view.Name == name {
+			return view, true
+		}
+	}
+
+	return View{}, false
+}
+
+// WithView returns a copy of the config with columns of the view.
+func (c *Config) WithView(view View) *Config {
+	cfg := *c
+
+	if len(view.Fields) > 0 {
+		cfg.Fields = view.Fields
+	}
+
+	return &cfg
+}
+
+// InputFormat describes how entries are stored in the log.
+type InputFormat string
+
+// Possible input formats.
+const (
+	// InputFormatLines is a log with an entry per line, like NDJSON.
+	InputFormatLines InputFormat = "lines"
+	// InputFormatJSON is a top-level JSON array of entries.
+	InputFormatJSON InputFormat = "json"
+	// InputFormatCSV is comma-separated values with a header row.
+	InputFormatCSV InputFormat = "csv"
+	// InputFormatTSV is tab-separated values with a header row.
+	InputFormatTSV InputFormat = "tsv"
+)
+
+// Unwrap describes a field with a JSON object or an array that is encoded
+// as a string, for example, the "log" field written by the docker json-file
+// logging driver.
+type Unwrap struct {
+	// Field is the name of the top-level field.
+	Field string `json:"field" validate:"required"`
+	// Merge moves the keys of the decoded object to the top level of the
+	// entry. Otherwise the decoded value replaces the string in the field.
+	Merge bool `json:"merge,omitempty"`
+}
+
+// Multiline describes the rules of joining several lines into a single
+// entry. A line continues the previous entry if any of enabled rules
+// matches it. By default, each line is a separate entry.
+type Multiline struct {
+	// Indentation joins lines that start with a space or a tab, for
+	// example, stack traces.
+	Indentation bool `json:"indentation,omitempty"`
+	// StartPattern is a regular expression that matches the first line of
+	// an entry. Lines that don't match it continue the previous entry.
+	StartPattern string `json:"startPattern,omitempty"`
+	// JSON joins lines of an entry that starts with "{" until all its
+	// braces are closed. It allows reading pretty-printed JSON.
+	JSON bool `json:"json,omitempty"`
+}
+
+// IsEnabled returns true if any rule is set.
+func (m Multiline) IsEnabled() bool {
+	return m.Indentation || m.StartPattern != "" || m.JSON
 }
 
 // FieldKind describes the type of the log field.
@@ -48,20 +290,59 @@ const (
 	FieldKindSecondTime  FieldKind = "secondtime"
 	FieldKindMilliTime   FieldKind = "millitime"
 	FieldKindMicroTime   FieldKind = "microtime"
+	FieldKindNanoTime    FieldKind = "nanotime"
 	FieldKindMessage     FieldKind = "message"
 	FieldKindLevel       FieldKind = "level"
 	FieldKindAny         FieldKind = "any"
+	FieldKindDuration    FieldKind = "duration"
+	FieldKindBytes       FieldKind = "bytes"
+	FieldKindNumber      FieldKind = "number"
+	FieldKindBool        FieldKind = "bool"
+	FieldKindJSON        FieldKind = "json"
 )
 
 // Field customization.
 type Field struct {
 	Title      string    `json:"title" validate:"required,min=1,max=32"`
-	Kind       FieldKind `json:"kind" validate:"required,oneof=time message numerictime secondtime millitime microtime level any"`
-	References []string  `json:"ref" validate:"min=1,dive,required"`
-	Width      int       `json:"width" validate:"min=0"`
+	Kind       FieldKind `json:"kind" validate:"required,oneof=time message numerictime secondtime millitime microtime nanotime level any duration bytes number bool json"`
+	References []string  `json:"ref,omitempty" validate:"required_without=Template,omitempty,min=1,dive,required"`
+	// Template renders the column from the JSON object instead of the
+	// references, see text/template.
+	Template string `json:"template,omitempty"`
+	Width    int    `json:"width" validate:"min=0"`
 
 	TimeFormatDeprecated *string `json:"time_format,omitempty"`
 	TimeFormat           *string `json:"timeFormat,omitempty"`
+	// TimeZone of the formatted time, it overrides the time zone of the
+	// config.
+	TimeZone string `json:"timeZone,omitempty"`
+	// Precision is the number of decimal places of a number, the value is
+	// not rounded if it is nil.
+	Precision *int `json:"precision,omitempty" validate:"omitempty,min=0,max=20"`
+	// Unit of numeric durations, milliseconds by default.
+	Unit string `json:"unit,omitempty" validate:"omitempty,oneof=ns us µs ms s m h"`
+}
+
+// IsTime returns true if the kind of the field is a time.
+func (f Field) IsTime() bool {
+	switch f.Kind {
+	case FieldKindTime, FieldKindNumericTime, FieldKindSecondTime,
+		FieldKindMilliTime, FieldKindMicroTime, FieldKindNanoTime:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsNumeric returns true if the field holds quantities, they are aligned
+// to the right.
+func (f Field) IsNumeric() bool {
+	switch f.Kind {
+	case FieldKindDuration, FieldKindBytes, FieldKindNumber:
+		return true
+	default:
+		return false
+	}
 }
 
 // GetDefaultConfig returns the configuration with default values.
@@ -113,8 +394,17 @@ func GetDefaultConfig() *Config {
 }
 
 // Read config from the given paths. From higher priority to lower priority.
+// All existing configs are merged: a config of higher priority overrides the
+// keys that it sets. Arrays are replaced, objects are merged.
 func Read(paths ...string) (*Config, error) {
-	cfg, err := readConfigFromPaths(paths...)
+	return ReadPreset("", paths...)
+}
+
+// ReadPreset reads config like Read, but the configs are based on the
+// built-in preset instead of the preset that they extend. The preset is
+// ignored if it is empty.
+func ReadPreset(preset string, paths ...string) (*Config, error) {
+	cfg, err := readConfigFromPaths(preset, paths...)
 	if err != nil {
 		return nil, fmt.Errorf("reading from paths: %w", err)
 	}
@@ -124,15 +414,50 @@ func Read(paths ...string) (*Config, error) {
 		return nil, fmt.Errorf("validating config: %s: %w", cfg.Path, err)
 	}
 
+	err = validateTimeZones(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("validating time zones: %s: %w", cfg.Path, err)
+	}
+
+	err = validateLevels(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("validating levels: %s: %w", cfg.Path, err)
+	}
+
+	_, err = keymap.New(cfg.Keys)
+	if err != nil {
+		return nil, fmt.Errorf("validating keys: %s: %w", cfg.Path, err)
+	}
+
+	_, err = regexp.Compile(cfg.Multiline.StartPattern)
+	if err != nil {
+		return nil, fmt.Errorf("compiling multiline start pattern: %s: %w", cfg.Path, err)
+	}
+
 	if cfg.CustomLevelMapping == nil {
 		cfg.CustomLevelMapping = map[string]string{}
 	}
 
+	for i, profile := range cfg.Profiles {
+		if profile.CustomLevelMapping == nil {
+			continue
+		}
+
+		mapping := maps.Clone(cfg.CustomLevelMapping)
+		maps.Copy(mapping, profile.CustomLevelMapping)
+
+		cfg.Profiles[i].CustomLevelMapping = mapping
+	}
+
+	cfg.resolveProfiles()
+
 	return cfg, nil
 }
 
-func readConfigFromPaths(paths ...string) (*Config, error) {
-	for _, p := range paths {
+func readConfigFromPaths(preset string, paths ...string) (*Config, error) {
+	var layers []configLayer
+
+	for _, p := range slices.Backward(paths) {
 		_, err := os.Stat(p)
 		if err != nil {
 			if errors.Is(err, os.ErrNotExist) {
@@ -142,38 +467,95 @@ func readConfigFromPaths(paths ...string) (*Config, error) {
 			return nil, fmt.Errorf("checking config: %w", err)
 		}
 
-		cfg, err := readConfigFromFile(p)
+		layer, err := readConfigLayer(p)
 		if err != nil {
 			return nil, fmt.Errorf("reading config from file: %w", err)
 		}
 
-		return cfg, nil
+		layers = append(layers, layer)
+	}
+
+	cfg := GetDefaultConfig()
+
+	if preset == "" {
+		// The preset of the config with the highest priority is used.
+		for _, layer := range layers {
+			if layer.Extends != "" {
+				preset = layer.Extends
+			}
+		}
+	}
+
+	if preset != "" {
+		if err := cfg.applyPreset(preset); err != nil {
+			return nil, err
+		}
+	}
+
+	builtinMapping := maps.Clone(cfg.CustomLevelMapping)
+
+	for _, layer := range layers {
+		err := layer.decode(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", layer.Path, err)
+		}
 	}
 
-	return GetDefaultConfig(), nil
+	cfg.Extends = preset
+	cfg.removeBuiltinLevelMapping(builtinMapping)
+
+	return cfg, nil
+}
+
+// configLayer is the content of a config file.
+type configLayer struct {
+	Path    string
+	Content []byte
+	// Extends is the preset of the config.
+	Extends string
 }
 
-func readConfigFromFile(path string) (cfg *Config, err error) {
+func readConfigLayer(path string) (layer configLayer, err error) {
 	file, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("os opening: %w", err)
+		return configLayer{}, fmt.Errorf("os opening: %w", err)
 	}
 
 	defer func() { err = errors.Join(err, file.Close()) }()
 
-	cfg = GetDefaultConfig()
+	content, err := io.ReadAll(jsoncjson.NewReader(file))
+	if err != nil {
+		return configLayer{}, fmt.Errorf("reading: %w", err)
+	}
+
+	var header struct {
+		Extends string `json:"extends"`
+	}
 
-	err = json.NewDecoder(
-		jsoncjson.NewReader(file),
-	).Decode(&cfg)
+	err = json.NewDecoder(bytes.NewReader(content)).Decode(&header)
 	if err != nil {
-		return nil, fmt.Errorf("decoding json: %w", err)
+		return configLayer{}, fmt.Errorf("decoding json: %w", err)
 	}
 
-	cfg.Path = path
+	return configLayer{
+		Path:    path,
+		Content: content,
+		Extends: header.Extends,
+	}, nil
+}
+
+// decode decodes the config file over the given config.
+func (l configLayer) decode(cfg *Config) error {
+	err := decodeLayer(cfg, l.Content)
+	if err != nil {
+		return fmt.Errorf("decoding json: %w", err)
+	}
+
+	cfg.Path = l.Path
 
 	if len(cfg.TimeLayoutsDeprecated) != 0 {
 		cfg.TimeLayouts = cfg.TimeLayoutsDeprecated
+		cfg.TimeLayoutsDeprecated = nil
 	}
 
 	for i, f := range cfg.Fields {
@@ -184,7 +566,39 @@ func readConfigFromFile(path string) (cfg *Config, err error) {
 		cfg.Fields[i] = f
 	}
 
-	return cfg, nil
+	return nil
+}
+
+// decodeLayer decodes the JSON object over the config. The decoder reuses
+// elements of existing arrays, so arrays that are set in the object are
+// cleared first, otherwise values of a lower layer would leak into them.
+func decodeLayer(cfg *Config, content []byte) error {
+	var keys map[string]json.RawMessage
+
+	err := json.NewDecoder(bytes.NewReader(content)).Decode(&keys)
+	if err != nil {
+		return err
+	}
+
+	value := reflect.ValueOf(cfg).Elem()
+
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		if field.Type.Kind() != reflect.Slice {
+			continue
+		}
+
+		// Keys are matched case-insensitively like the decoder does.
+		for key := range keys {
+			if strings.EqualFold(key, name) {
+				value.Field(i).SetZero()
+			}
+		}
+	}
+
+	return json.NewDecoder(bytes.NewReader(content)).Decode(cfg)
 }
 
 // GetDefaultCustomLevelMapping returns the custom mapping of levels.
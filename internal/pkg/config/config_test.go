@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hedhyw/json-log-viewer/internal/keymap"
 	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
 	"github.com/hedhyw/json-log-viewer/internal/pkg/tests"
 )
@@ -54,6 +55,38 @@ func TestReadPriority(t *testing.T) {
 	}
 }
 
+func TestReadMerge(t *testing.T) {
+	t.Parallel()
+
+	fileLower := tests.RequireCreateFile(t, []byte(`{
+		// The lower config.
+		"fields": [
+			{"title": "Level", "kind": "level", "ref": ["$.level"], "width": 10},
+			{"title": "Message", "kind": "message", "ref": ["$.message"]}
+		],
+		"customLevelMapping": {"1": "info"},
+		"isReverseDefault": false
+	}`))
+	fileHigher := tests.RequireCreateFile(t, []byte(`{
+		"fields": [{"title": "Text", "kind": "message", "ref": ["$.text"]}],
+		"customLevelMapping": {"2": "error"}
+	}`))
+
+	cfg, err := config.Read(fileHigher, fileLower)
+	require.NoError(t, err)
+
+	assert.Equal(t, fileHigher, cfg.Path)
+	assert.Equal(t, []config.Field{{
+		Title:      "Text",
+		Kind:       config.FieldKindMessage,
+		References: []string{"$.text"},
+	}}, cfg.Fields)
+	assert.Equal(t, "info", cfg.CustomLevelMapping["1"])
+	assert.Equal(t, "error", cfg.CustomLevelMapping["2"])
+	assert.False(t, cfg.IsReverseDefault)
+	assert.Equal(t, config.GetDefaultConfig().TimeLayouts, cfg.TimeLayouts)
+}
+
 func TestReadValidated(t *testing.T) {
 	t.Parallel()
 
@@ -69,6 +102,555 @@ func TestReadValidated(t *testing.T) {
 	}
 }
 
+func TestReadInvalidMultilineStartPattern(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	cfg.Multiline.StartPattern = "("
+
+	configJSON := tests.RequireEncodeJSON(t, cfg)
+	configFile := tests.RequireCreateFile(t, configJSON)
+
+	_, err := config.Read(configFile)
+	require.Error(t, err)
+}
+
+func TestReadKeys(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.GetDefaultConfig()
+		cfg.Keys = map[string][]string{"filter": {"/"}}
+
+		configFile := tests.RequireCreateFile(t, tests.RequireEncodeJSON(t, cfg))
+
+		actual, err := config.Read(configFile)
+		require.NoError(t, err)
+		assert.Equal(t, cfg.Keys, actual.Keys)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.GetDefaultConfig()
+		cfg.Keys = map[string][]string{"filter": {"r"}}
+
+		configFile := tests.RequireCreateFile(t, tests.RequireEncodeJSON(t, cfg))
+
+		_, err := config.Read(configFile)
+		require.ErrorIs(t, err, keymap.ErrKeyConflict)
+	})
+}
+
+func TestReadTheme(t *testing.T) {
+	t.Parallel()
+
+	testCases := [...]struct {
+		Name  string
+		Theme config.Theme
+		Valid bool
+	}{{
+		Name:  "default",
+		Valid: true,
+	}, {
+		Name: "custom",
+		Theme: config.Theme{
+			Name:   config.ThemeLight,
+			Levels: map[string]string{"error": "#FF0000", "info": "28"},
+			Header: config.ThemeStyle{Foreground: "15", Bold: true},
+		},
+		Valid: true,
+	}, {
+		Name:  "unknown_name",
+		Theme: config.Theme{Name: "solarized"},
+	}, {
+		Name:  "unknown_level",
+		Theme: config.Theme{Levels: map[string]string{"notice": "28"}},
+	}, {
+		Name:  "invalid_color",
+		Theme: config.Theme{Selected: config.ThemeStyle{Background: "purple"}},
+	}, {
+		Name:  "unknown_json_theme",
+		Theme: config.Theme{JSON: "2"},
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.GetDefaultConfig()
+			cfg.Theme = testCase.Theme
+
+			configFile := tests.RequireCreateFile(t, tests.RequireEncodeJSON(t, cfg))
+
+			actual, err := config.Read(configFile)
+			if !testCase.Valid {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.Theme, actual.Theme)
+		})
+	}
+}
+
+func TestReadHighlights(t *testing.T) {
+	t.Parallel()
+
+	testCases := [...]struct {
+		Name      string
+		Highlight config.Highlight
+		Valid     bool
+	}{{
+		Name:      "term",
+		Highlight: config.Highlight{Term: "tenant-42", Style: config.ThemeStyle{Background: "236"}},
+		Valid:     true,
+	}, {
+		Name:      "compare",
+		Highlight: config.Highlight{Field: "$.duration_ms", Op: ">", Value: "1000", Column: "Message"},
+		Valid:     true,
+	}, {
+		Name:      "level",
+		Highlight: config.Highlight{Level: "warn"},
+		Valid:     true,
+	}, {
+		Name:      "no_conditions",
+		Highlight: config.Highlight{Column: "Message"},
+	}, {
+		Name:      "unknown_op",
+		Highlight: config.Highlight{Field: "$.duration_ms", Op: "~", Value: "1"},
+	}, {
+		Name:      "op_without_field",
+		Highlight: config.Highlight{Op: "==", Value: "1"},
+	}, {
+		Name:      "invalid_color",
+		Highlight: config.Highlight{Level: "warn", Style: config.ThemeStyle{Foreground: "red"}},
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.GetDefaultConfig()
+			cfg.Highlights = []config.Highlight{testCase.Highlight}
+
+			configFile := tests.RequireCreateFile(t, tests.RequireEncodeJSON(t, cfg))
+
+			actual, err := config.Read(configFile)
+			if !testCase.Valid {
+				require.ErrorAs(t, err, &validator.ValidationErrors{})
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, cfg.Highlights, actual.Highlights)
+		})
+	}
+}
+
+func TestReadLevels(t *testing.T) {
+	t.Parallel()
+
+	levels := []config.Level{
+		{Name: "info", Range: []float64{30, 39}},
+		{Name: "notice", Aliases: []string{"note"}, Color: "14"},
+		{Name: "critical"},
+	}
+
+	testCases := [...]struct {
+		Name   string
+		Levels []config.Level
+		Setter func(cfg *config.Config)
+		Error  error
+	}{{
+		Name:   "valid",
+		Levels: levels,
+		Setter: func(cfg *config.Config) {
+			cfg.Views = []config.View{{Name: "critical", MinLevel: "Critical"}}
+			cfg.Highlights = []config.Highlight{{Level: "notice"}}
+			cfg.Theme.Levels = map[string]string{"critical": "9"}
+		},
+	}, {
+		Name:   "undeclared_min_level",
+		Levels: levels,
+		Setter: func(cfg *config.Config) {
+			cfg.Views = []config.View{{Name: "errors", MinLevel: "error"}}
+		},
+		Error: config.ErrUnknownLevel,
+	}, {
+		Name:   "undeclared_highlight",
+		Levels: levels,
+		Setter: func(cfg *config.Config) {
+			cfg.Highlights = []config.Highlight{{Level: "fatal"}}
+		},
+		Error: config.ErrUnknownLevel,
+	}, {
+		Name:   "undeclared_theme",
+		Setter: func(cfg *config.Config) { cfg.Theme.Levels = map[string]string{"notice": "9"} },
+		Error:  config.ErrUnknownLevel,
+	}, {
+		Name:   "undeclared_mapping",
+		Levels: levels,
+		Setter: func(cfg *config.Config) { cfg.CustomLevelMapping = map[string]string{"warning": "warn"} },
+		Error:  config.ErrUnknownLevel,
+	}, {
+		Name:   "undeclared_profile_mapping",
+		Levels: levels,
+		Setter: func(cfg *config.Config) {
+			cfg.Profiles = []config.Profile{{
+				Name:               "gcp",
+				Match:              config.ProfileMatch{Keys: []string{"$.severity"}},
+				Fields:             cfg.Fields,
+				CustomLevelMapping: map[string]string{"alert": "fatal"},
+			}}
+		},
+		Error: config.ErrUnknownLevel,
+	}, {
+		Name:   "invalid_range",
+		Levels: []config.Level{{Name: "info", Range: []float64{39, 30}}},
+		Error:  config.ErrUnknownLevel,
+	}, {
+		Name:   "duplicated",
+		Levels: []config.Level{{Name: "info"}, {Name: "info"}},
+		Error:  validator.ValidationErrors{},
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.GetDefaultConfig()
+			cfg.Levels = testCase.Levels
+
+			if testCase.Setter != nil {
+				testCase.Setter(cfg)
+			}
+
+			configFile := tests.RequireCreateFile(t, tests.RequireEncodeJSON(t, cfg))
+
+			actual, err := config.Read(configFile)
+
+			switch expected := testCase.Error.(type) {
+			case nil:
+				require.NoError(t, err)
+				assert.Equal(t, testCase.Levels, actual.Levels)
+				assert.Equal(t, []string{"info", "notice", "critical"}, actual.LevelNames())
+
+				// The default mapping of pino is kept only for declared levels.
+				assert.Equal(t, "info", actual.CustomLevelMapping["30"])
+				assert.NotContains(t, actual.CustomLevelMapping, "60")
+			case validator.ValidationErrors:
+				require.ErrorAs(t, err, &expected)
+			default:
+				require.ErrorIs(t, err, expected)
+			}
+		})
+	}
+}
+
+func TestReadTimeZone(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.GetDefaultConfig()
+		cfg.TimeZone = "local"
+		cfg.Fields[0].TimeZone = "Europe/Berlin"
+
+		configFile := tests.RequireCreateFile(t, tests.RequireEncodeJSON(t, cfg))
+
+		actual, err := config.Read(configFile)
+		require.NoError(t, err)
+
+		assert.Equal(t, time.Local, actual.Location(actual.Fields[1]))
+		assert.Equal(t, "Europe/Berlin", actual.Location(actual.Fields[0]).String())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.GetDefaultConfig()
+		cfg.Fields[0].TimeZone = "Mars/Olympus"
+
+		configFile := tests.RequireCreateFile(t, tests.RequireEncodeJSON(t, cfg))
+
+		_, err := config.Read(configFile)
+		require.ErrorContains(t, err, "Mars/Olympus")
+	})
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.GetDefaultConfig()
+		assert.Nil(t, cfg.Location(cfg.Fields[0]))
+	})
+}
+
+func TestReadViews(t *testing.T) {
+	t.Parallel()
+
+	reverse := false
+
+	testCases := [...]struct {
+		Name    string
+		Views   []config.View
+		IsValid bool
+	}{{
+		Name: "ok",
+		Views: []config.View{{
+			Name:     "errors",
+			Filter:   "timeout",
+			MinLevel: "error",
+			Reverse:  &reverse,
+		}, {
+			Name: "messages",
+			Fields: []config.Field{{
+				Title:      "Message",
+				Kind:       config.FieldKindMessage,
+				References: []string{"$.message"},
+			}},
+		}},
+		IsValid: true,
+	}, {
+		Name:    "unset_name",
+		Views:   []config.View{{Filter: "timeout"}},
+		IsValid: false,
+	}, {
+		Name:    "duplicated_name",
+		Views:   []config.View{{Name: "errors"}, {Name: "errors"}},
+		IsValid: false,
+	}, {
+		Name:    "invalid_min_level",
+		Views:   []config.View{{Name: "errors", MinLevel: "critical"}},
+		IsValid: false,
+	}, {
+		Name:    "invalid_field",
+		Views:   []config.View{{Name: "errors", Fields: []config.Field{{Title: "Message"}}}},
+		IsValid: false,
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.GetDefaultConfig()
+			cfg.Views = testCase.Views
+
+			configFile := tests.RequireCreateFile(t, tests.RequireEncodeJSON(t, cfg))
+
+			actual, err := config.Read(configFile)
+			if !testCase.IsValid {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.Views, actual.Views)
+		})
+	}
+}
+
+func TestConfigView(t *testing.T) {
+	t.Parallel()
+
+	fields := []config.Field{{
+		Title:      "Message",
+		Kind:       config.FieldKindMessage,
+		References: []string{"$.message"},
+	}}
+
+	cfg := config.GetDefaultConfig()
+	cfg.Views = []config.View{{Name: "errors"}, {Name: "messages", Fields: fields}}
+
+	_, ok := cfg.View("unknown")
+	assert.False(t, ok)
+
+	view, ok := cfg.View("errors")
+	require.True(t, ok)
+	assert.Equal(t, cfg.Fields, cfg.WithView(view).Fields)
+
+	view, ok = cfg.View("messages")
+	require.True(t, ok)
+
+	viewConfig := cfg.WithView(view)
+	assert.Equal(t, fields, viewConfig.Fields)
+	assert.Len(t, cfg.Fields, 3, "the original config is not changed")
+}
+
+func TestReadProfiles(t *testing.T) {
+	t.Parallel()
+
+	fields := config.GetDefaultConfig().Fields
+
+	testCases := [...]struct {
+		Name     string
+		Profiles []config.Profile
+		IsValid  bool
+	}{{
+		Name: "ok",
+		Profiles: []config.Profile{{
+			Name:   "nginx",
+			Match:  config.ProfileMatch{Files: []string{"nginx-*.log"}},
+			Fields: fields,
+		}, {
+			Name:   "gcp",
+			Match:  config.ProfileMatch{Keys: []string{"$.severity"}},
+			Fields: fields,
+		}},
+		IsValid: true,
+	}, {
+		Name:     "without_rules",
+		Profiles: []config.Profile{{Name: "nginx", Fields: fields}},
+		IsValid:  false,
+	}, {
+		Name: "empty_key",
+		Profiles: []config.Profile{{
+			Name:   "gcp",
+			Match:  config.ProfileMatch{Keys: []string{""}},
+			Fields: fields,
+		}},
+		IsValid: false,
+	}, {
+		Name: "without_fields",
+		Profiles: []config.Profile{{
+			Name:  "gcp",
+			Match: config.ProfileMatch{Keys: []string{"$.severity"}},
+		}},
+		IsValid: false,
+	}, {
+		Name: "duplicated_name",
+		Profiles: []config.Profile{{
+			Name:   "gcp",
+			Match:  config.ProfileMatch{Keys: []string{"$.severity"}},
+			Fields: fields,
+		}, {
+			Name:   "gcp",
+			Match:  config.ProfileMatch{Keys: []string{"$.level"}},
+			Fields: fields,
+		}},
+		IsValid: false,
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.GetDefaultConfig()
+			cfg.Profiles = testCase.Profiles
+
+			configFile := tests.RequireCreateFile(t, tests.RequireEncodeJSON(t, cfg))
+
+			actual, err := config.Read(configFile)
+			if !testCase.IsValid {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.Profiles, actual.Profiles)
+		})
+	}
+}
+
+func TestReadProfileLevelMapping(t *testing.T) {
+	t.Parallel()
+
+	configFile := tests.RequireCreateFile(t, []byte(`{
+		"profiles": [{
+			"name": "gcp",
+			"match": {"keys": ["$.severity"]},
+			"fields": [{"title": "Level", "kind": "level", "ref": ["$.severity"]}],
+			"customLevelMapping": {"notice": "info"}
+		}]
+	}`))
+
+	cfg, err := config.Read(configFile)
+	require.NoError(t, err)
+
+	mapping := cfg.WithProfile(cfg.Profiles[0]).CustomLevelMapping
+	assert.Equal(t, "info", mapping["notice"])
+	assert.Equal(t, "trace", mapping["10"], "the mapping of the config is kept")
+	assert.NotContains(t, cfg.CustomLevelMapping, "notice")
+}
+
+func TestConfigProfileConfig(t *testing.T) {
+	t.Parallel()
+
+	configFile := tests.RequireCreateFile(t, []byte(`{
+		"profiles": [{
+			"name": "gcp",
+			"match": {"keys": ["$.severity"]},
+			"fields": [{"title": "Level", "kind": "level", "ref": ["$.severity"]}]
+		}]
+	}`))
+
+	cfg, err := config.Read(configFile)
+	require.NoError(t, err)
+
+	// The config of the profile is created once.
+	actual := cfg.ProfileConfig(cfg.Profiles[0])
+	assert.Same(t, actual, cfg.ProfileConfig(cfg.Profiles[0]))
+	assert.Equal(t, "gcp", actual.Profile)
+	assert.Equal(t, cfg.Profiles[0].Fields, actual.Fields)
+
+	// Configs that are not read create it.
+	cfg = config.GetDefaultConfig()
+	assert.Equal(t, "gcp", cfg.ProfileConfig(config.Profile{Name: "gcp"}).Profile)
+}
+
+func TestConfigProfileForFile(t *testing.T) {
+	t.Parallel()
+
+	fields := []config.Field{{
+		Title:      "Message",
+		Kind:       config.FieldKindMessage,
+		References: []string{"$.message"},
+	}}
+
+	cfg := config.GetDefaultConfig()
+	cfg.Profiles = []config.Profile{{
+		Name:   "gcp",
+		Match:  config.ProfileMatch{Keys: []string{"$.severity"}},
+		Fields: fields,
+	}, {
+		Name:   "nginx",
+		Match:  config.ProfileMatch{Files: []string{"nginx-*.log", "/var/log/proxy/*"}},
+		Fields: fields,
+	}}
+
+	testCases := [...]struct {
+		FileName string
+		Expected string
+	}{
+		{FileName: "logs/nginx-access.log", Expected: "nginx"},
+		{FileName: "/var/log/proxy/access", Expected: "nginx"},
+		{FileName: "logs/app.log", Expected: ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.FileName, func(t *testing.T) {
+			t.Parallel()
+
+			profile, ok := cfg.ProfileForFile(testCase.FileName)
+			assert.Equal(t, testCase.Expected != "", ok)
+			assert.Equal(t, testCase.Expected, profile.Name)
+		})
+	}
+
+	profileConfig := cfg.WithProfile(cfg.Profiles[1])
+	assert.Equal(t, "nginx", profileConfig.Profile)
+	assert.Equal(t, fields, profileConfig.Fields)
+	assert.Len(t, cfg.Fields, 3, "the original config is not changed")
+}
+
 func TestReadInvalidJSON(t *testing.T) {
 	t.Parallel()
 
@@ -265,6 +847,56 @@ func TestValidateField(t *testing.T) {
 			value.Kind = config.FieldKindMicroTime
 		},
 		IsValid: true,
+	}, {
+		Name: "kind_duration_unit",
+		Apply: func(value *config.Field) {
+			value.Kind = config.FieldKindDuration
+			value.Unit = "us"
+		},
+		IsValid: true,
+	}, {
+		Name: "invalid_unit",
+		Apply: func(value *config.Field) {
+			value.Kind = config.FieldKindDuration
+			value.Unit = "days"
+		},
+		IsValid: false,
+	}, {
+		Name: "kind_number_precision",
+		Apply: func(value *config.Field) {
+			precision := 0
+
+			value.Kind = config.FieldKindNumber
+			value.Precision = &precision
+		},
+		IsValid: true,
+	}, {
+		Name: "negative_precision",
+		Apply: func(value *config.Field) {
+			precision := -1
+
+			value.Kind = config.FieldKindNumber
+			value.Precision = &precision
+		},
+		IsValid: false,
+	}, {
+		Name: "kind_bytes",
+		Apply: func(value *config.Field) {
+			value.Kind = config.FieldKindBytes
+		},
+		IsValid: true,
+	}, {
+		Name: "kind_bool",
+		Apply: func(value *config.Field) {
+			value.Kind = config.FieldKindBool
+		},
+		IsValid: true,
+	}, {
+		Name: "kind_json",
+		Apply: func(value *config.Field) {
+			value.Kind = config.FieldKindJSON
+		},
+		IsValid: true,
 	}, {
 		Name: "unset_kind",
 		Apply: func(value *config.Field) {
@@ -277,6 +909,25 @@ func TestValidateField(t *testing.T) {
 			value.Kind = "invalid"
 		},
 		IsValid: false,
+	}, {
+		Name: "template",
+		Apply: func(value *config.Field) {
+			value.References = nil
+			value.Template = "{{ .method }} {{ .path }}"
+		},
+		IsValid: true,
+	}, {
+		Name: "unset_references",
+		Apply: func(value *config.Field) {
+			value.References = nil
+		},
+		IsValid: false,
+	}, {
+		Name: "empty_references",
+		Apply: func(value *config.Field) {
+			value.References = []string{}
+		},
+		IsValid: false,
 	}, {
 		Name: "unset_width",
 		Apply: func(value *config.Field) {
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hedhyw/semerr/pkg/v1/semerr"
+)
+
+// ErrUnknownLevel is returned if the config refers to a level that is not
+// declared.
+const ErrUnknownLevel semerr.Error = "unknown level"
+
+// builtinLevelNames are names of built-in levels from the least severe.
+var builtinLevelNames = []string{"trace", "debug", "info", "warn", "error", "panic", "fatal"}
+
+// Level is a level of entries that replaces built-in levels. Levels of the
+// config are ordered from the least severe.
+type Level struct {
+	// Name is shown in the level column, like "notice".
+	Name string `json:"name" validate:"required"`
+	// Aliases are other names of the level in logs. They are matched
+	// case-insensitively.
+	Aliases []string `json:"aliases,omitempty" validate:"dive,required"`
+	// Range matches numeric levels from the first value to the second one
+	// inclusively, for example, [30, 39].
+	Range []float64 `json:"range,omitempty" validate:"omitempty,len=2"`
+	// Color of the level, it overrides the color of the theme.
+	Color string `json:"color,omitempty" validate:"omitempty,hexcolor|numeric"`
+}
+
+// LevelNames returns names of levels from the least severe. They are the
+// built-in levels if the config doesn't declare levels.
+func (c *Config) LevelNames() []string {
+	if len(c.Levels) == 0 {
+		return slices.Clone(builtinLevelNames)
+	}
+
+	names := make([]string, 0, len(c.Levels))
+	for _, level := range c.Levels {
+		names = append(names, strings.ToLower(level.Name))
+	}
+
+	return names
+}
+
+// removeBuiltinLevelMapping removes values of the built-in mapping, like
+// numbers of pino, that refer to built-in levels which the config doesn't
+// declare. Values that are set by configs are kept.
+func (c *Config) removeBuiltinLevelMapping(builtin map[string]string) {
+	if len(c.Levels) == 0 {
+		return
+	}
+
+	names := c.LevelNames()
+
+	for value, level := range builtin {
+		if c.CustomLevelMapping[value] == level && !slices.Contains(names, strings.ToLower(level)) {
+			delete(c.CustomLevelMapping, value)
+		}
+	}
+}
+
+// validateLevels checks that the config refers only to declared levels.
+func validateLevels(cfg *Config) error {
+	names := cfg.LevelNames()
+
+	check := func(where string, name string) error {
+		if name == "" || slices.Contains(names, strings.ToLower(name)) {
+			return nil
+		}
+
+		return fmt.Errorf("%w: %s: %s, levels: %s", ErrUnknownLevel, where, name, strings.Join(names, ", "))
+	}
+
+	for _, level := range cfg.Levels {
+		if len(level.Range) == 2 && level.Range[0] > level.Range[1] {
+			return fmt.Errorf("%w: %s: invalid range: %v", ErrUnknownLevel, level.Name, level.Range)
+		}
+	}
+
+	for _, view := range cfg.Views {
+		if err := check("view "+view.Name, view.MinLevel); err != nil {
+			return err
+		}
+	}
+
+	for i, highlight := range cfg.Highlights {
+		if err := check(fmt.Sprintf("highlight %d", i), highlight.Level); err != nil {
+			return err
+		}
+	}
+
+	for name := range cfg.Theme.Levels {
+		if err := check("theme", name); err != nil {
+			return err
+		}
+	}
+
+	// Without declared levels, values that are not built-in levels are
+	// shown as they are.
+	if len(cfg.Levels) == 0 {
+		return nil
+	}
+
+	for value, name := range cfg.CustomLevelMapping {
+		if err := check("customLevelMapping "+value, name); err != nil {
+			return err
+		}
+	}
+
+	for _, profile := range cfg.Profiles {
+		for value, name := range profile.CustomLevelMapping {
+			if err := check("profile "+profile.Name+" customLevelMapping "+value, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
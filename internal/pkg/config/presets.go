@@ -0,0 +1,199 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/hedhyw/semerr/pkg/v1/semerr"
+)
+
+// ErrUnknownPreset is returned if there is no built-in preset with the name.
+const ErrUnknownPreset semerr.Error = "unknown preset"
+
+// Names of built-in presets.
+const (
+	PresetZap        = "zap"
+	PresetZerolog    = "zerolog"
+	PresetLogrus     = "logrus"
+	PresetSlog       = "slog"
+	PresetPino       = "pino"
+	PresetBunyan     = "bunyan"
+	PresetSerilog    = "serilog"
+	PresetGCP        = "gcp"
+	PresetCloudWatch = "cloudwatch"
+	PresetECS        = "ecs"
+)
+
+// Widths of the time and the level columns in presets.
+const (
+	presetTimeWidth  = 30
+	presetLevelWidth = 10
+)
+
+// presets configure columns, time layouts and levels of popular loggers on
+// top of the default config.
+//
+// nolint: mnd // Widths of columns.
+var presets = map[string]func(cfg *Config){
+	PresetZap: func(cfg *Config) {
+		// The production encoder writes epoch seconds, the development one
+		// writes ISO 8601 time with milliseconds.
+		cfg.TimeLayouts = append(cfg.TimeLayouts, "2006-01-02T15:04:05.000Z0700")
+		cfg.Fields = []Field{
+			timeField(FieldKindNumericTime, "$.ts"),
+			levelField("$.level"),
+			anyField("Caller", 20, "$.caller"),
+			messageField("$.msg", "$.error"),
+		}
+	},
+	PresetZerolog: func(cfg *Config) {
+		cfg.Fields = []Field{
+			timeField(FieldKindNumericTime, "$.time"),
+			levelField("$.level"),
+			messageField("$.message", "$.error"),
+		}
+	},
+	PresetLogrus: func(cfg *Config) {
+		cfg.Fields = []Field{
+			timeField(FieldKindTime, "$.time"),
+			levelField("$.level"),
+			messageField("$.msg", "$.error"),
+		}
+	},
+	PresetSlog: func(cfg *Config) {
+		cfg.Fields = []Field{
+			timeField(FieldKindTime, "$.time"),
+			levelField("$.level"),
+			messageField("$.msg"),
+		}
+	},
+	PresetPino: func(cfg *Config) {
+		cfg.Fields = []Field{
+			timeField(FieldKindNumericTime, "$.time"),
+			levelField("$.level"),
+			messageField("$.msg", "$.err.message"),
+		}
+	},
+	PresetBunyan: func(cfg *Config) {
+		// Bunyan uses the same numeric levels as pino.
+		cfg.Fields = []Field{
+			timeField(FieldKindTime, "$.time"),
+			levelField("$.level"),
+			anyField("Name", 15, "$.name"),
+			messageField("$.msg", "$.err.message"),
+		}
+	},
+	PresetSerilog: func(cfg *Config) {
+		// Compact log event format, "@l" is omitted for the information
+		// level, "@m" is set only if the message is rendered.
+		cfg.CustomLevelMapping["verbose"] = levelTrace
+		cfg.CustomLevelMapping["information"] = levelInfo
+		cfg.CustomLevelMapping["warning"] = levelWarn
+		cfg.Fields = []Field{
+			timeField(FieldKindTime, `$["@t"]`),
+			levelField(`$["@l"]`),
+			messageField(`$["@m"]`, `$["@mt"]`, `$["@x"]`),
+		}
+	},
+	PresetGCP: func(cfg *Config) {
+		// The default severity means that the level is not set.
+		cfg.CustomLevelMapping["default"] = levelNone
+		cfg.CustomLevelMapping["notice"] = levelInfo
+		cfg.CustomLevelMapping["warning"] = levelWarn
+		cfg.CustomLevelMapping["critical"] = levelFatal
+		cfg.CustomLevelMapping["alert"] = levelFatal
+		cfg.CustomLevelMapping["emergency"] = levelFatal
+		cfg.Fields = []Field{
+			timeField(FieldKindTime, "$.timestamp", "$.receiveTimestamp"),
+			levelField("$.severity"),
+			messageField("$.textPayload", "$.jsonPayload.message", "$.jsonPayload.msg", "$.protoPayload.status.message"),
+		}
+	},
+	PresetCloudWatch: func(cfg *Config) {
+		// Events of exports and of "aws logs filter-log-events" keep the
+		// original line in "message", it is decoded if it is JSON.
+		cfg.Unwrap = append(cfg.Unwrap, Unwrap{Field: "message", Merge: true})
+		cfg.Fields = []Field{
+			timeField(FieldKindNumericTime, "$.timestamp"),
+			levelField("$.level", "$.lvl", "$.severity"),
+			anyField("Stream", 20, "$.logStreamName", "$.logStream"),
+			messageField("$.message", "$.msg"),
+		}
+	},
+	PresetECS: func(cfg *Config) {
+		// ECS loggers write dotted keys, others nest objects.
+		cfg.Fields = []Field{
+			timeField(FieldKindTime, `$["@timestamp"]`),
+			levelField(`$["log.level"]`, "$.log.level"),
+			anyField("Service", 15, `$["service.name"]`, "$.service.name"),
+			messageField("$.message", `$["error.message"]`, "$.error.message"),
+		}
+	},
+}
+
+// Levels that are used in mappings of presets.
+const (
+	levelNone  = "none"
+	levelTrace = "trace"
+	levelInfo  = "info"
+	levelWarn  = "warn"
+	levelFatal = "fatal"
+)
+
+// PresetNames returns the sorted names of built-in presets.
+func PresetNames() []string {
+	return slices.Sorted(maps.Keys(presets))
+}
+
+// applyPreset configures the config by the preset.
+func (c *Config) applyPreset(name string) error {
+	preset, ok := presets[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownPreset, name)
+	}
+
+	c.Extends = name
+
+	preset(c)
+
+	return nil
+}
+
+func timeField(kind FieldKind, refs ...string) Field {
+	timeFormat := DefaultTimeFormat
+
+	return Field{
+		Title:      "Time",
+		Kind:       kind,
+		References: refs,
+		Width:      presetTimeWidth,
+		TimeFormat: &timeFormat,
+	}
+}
+
+func levelField(refs ...string) Field {
+	return Field{
+		Title:      "Level",
+		Kind:       FieldKindLevel,
+		References: refs,
+		Width:      presetLevelWidth,
+	}
+}
+
+func anyField(title string, width int, refs ...string) Field {
+	return Field{
+		Title:      title,
+		Kind:       FieldKindAny,
+		References: refs,
+		Width:      width,
+	}
+}
+
+func messageField(refs ...string) Field {
+	return Field{
+		Title:      "Message",
+		Kind:       FieldKindMessage,
+		References: refs,
+	}
+}
@@ -0,0 +1,135 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/tests"
+)
+
+func TestReadPresets(t *testing.T) {
+	t.Parallel()
+
+	testCases := [...]struct {
+		Preset   string
+		Line     string
+		Expected []string
+	}{{
+		Preset:   config.PresetZap,
+		Line:     `{"level":"info","ts":1700000000.5,"caller":"main.go:10","msg":"started"}`,
+		Expected: []string{"2023-11-14T22:13:20Z", "info", "main.go:10", "started"},
+	}, {
+		Preset:   config.PresetZerolog,
+		Line:     `{"level":"warn","time":"2023-11-14T22:13:20Z","message":"slow"}`,
+		Expected: []string{"2023-11-14T22:13:20Z", "warn", "slow"},
+	}, {
+		Preset:   config.PresetLogrus,
+		Line:     `{"level":"error","time":"2023-11-14T22:13:20Z","msg":"failed","error":"timeout"}`,
+		Expected: []string{"2023-11-14T22:13:20Z", "error", "failed"},
+	}, {
+		Preset:   config.PresetSlog,
+		Line:     `{"time":"2023-11-14T22:13:20.123456789Z","level":"DEBUG","msg":"query"}`,
+		Expected: []string{"2023-11-14T22:13:20Z", "debug", "query"},
+	}, {
+		Preset:   config.PresetPino,
+		Line:     `{"level":50,"time":1700000000000,"pid":1,"hostname":"h","msg":"boom"}`,
+		Expected: []string{"2023-11-14T22:13:20Z", "error", "boom"},
+	}, {
+		Preset:   config.PresetBunyan,
+		Line:     `{"name":"api","hostname":"h","pid":1,"level":30,"msg":"listening","time":"2023-11-14T22:13:20.000Z","v":0}`,
+		Expected: []string{"2023-11-14T22:13:20Z", "info", "api", "listening"},
+	}, {
+		Preset:   config.PresetSerilog,
+		Line:     `{"@t":"2023-11-14T22:13:20.0000000Z","@mt":"Hello {User}","@l":"Warning","User":"x"}`,
+		Expected: []string{"2023-11-14T22:13:20Z", "warn", "Hello {User}"},
+	}, {
+		Preset:   config.PresetGCP,
+		Line:     `{"severity":"NOTICE","jsonPayload":{"message":"deployed"},"timestamp":"2023-11-14T22:13:20Z"}`,
+		Expected: []string{"2023-11-14T22:13:20Z", "info", "deployed"},
+	}, {
+		Preset:   config.PresetCloudWatch,
+		Line:     `{"timestamp":1700000000000,"logStreamName":"app/1","message":"{\"level\":\"error\",\"msg\":\"failed\"}"}`,
+		Expected: []string{"2023-11-14T22:13:20Z", "error", "app/1", "failed"},
+	}, {
+		Preset:   config.PresetECS,
+		Line:     `{"@timestamp":"2023-11-14T22:13:20.000Z","log.level":"info","message":"ready","service.name":"api","ecs.version":"1.6.0"}`,
+		Expected: []string{"2023-11-14T22:13:20Z", "info", "api", "ready"},
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Preset, func(t *testing.T) {
+			t.Parallel()
+
+			configFile := tests.RequireCreateFile(t, []byte(`{"extends": "`+testCase.Preset+`"}`))
+
+			cfg, err := config.Read(configFile)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.Preset, cfg.Extends)
+
+			inputSource, err := source.Reader(strings.NewReader(testCase.Line+"\n"), cfg)
+			require.NoError(t, err)
+
+			t.Cleanup(func() { assert.NoError(t, inputSource.Close()) })
+
+			entries, err := inputSource.ParseLogEntries()
+			require.NoError(t, err)
+			require.Equal(t, 1, entries.Len())
+
+			assert.Equal(t, testCase.Expected, entries.LogEntry(cfg, 0).Fields)
+		})
+	}
+}
+
+func TestReadPresetPriority(t *testing.T) {
+	t.Parallel()
+
+	fileLower := tests.RequireCreateFile(t, []byte(`{"extends": "pino"}`))
+	fileHigher := tests.RequireCreateFile(t, []byte(`{
+		"extends": "zap",
+		"fields": [{"title": "Message", "kind": "message", "ref": ["$.msg"]}]
+	}`))
+	fileOverride := tests.RequireCreateFile(t, []byte(`{"isReverseDefault": false}`))
+
+	t.Run("highest_config", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := config.Read(fileOverride, fileHigher, fileLower)
+		require.NoError(t, err)
+
+		assert.Equal(t, config.PresetZap, cfg.Extends)
+		assert.Len(t, cfg.Fields, 1, "fields of the config override the preset")
+		assert.Contains(t, cfg.TimeLayouts, "2006-01-02T15:04:05.000Z0700")
+		assert.False(t, cfg.IsReverseDefault)
+	})
+
+	t.Run("flag", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := config.ReadPreset(config.PresetGCP, fileLower)
+		require.NoError(t, err)
+
+		assert.Equal(t, config.PresetGCP, cfg.Extends)
+		assert.Equal(t, []string{"$.severity"}, cfg.Fields[1].References)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := config.ReadPreset("log4j")
+		require.ErrorIs(t, err, config.ErrUnknownPreset)
+	})
+}
+
+func TestPresetNames(t *testing.T) {
+	t.Parallel()
+
+	names := config.PresetNames()
+
+	assert.Len(t, names, 10)
+	assert.IsIncreasing(t, names)
+}
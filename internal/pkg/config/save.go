@@ -0,0 +1,337 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/hedhyw/semerr/pkg/v1/semerr"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/atomicfile"
+)
+
+// FileName is the name of the config that is looked up in directories.
+const FileName = ".jlv.jsonc"
+
+const (
+	// ErrNotDeclared is returned if the view or the profile is not declared
+	// in the config file.
+	ErrNotDeclared semerr.Error = "not declared in the config"
+	// errObjectExpected is returned if a value of the config file is not
+	// an object.
+	errObjectExpected semerr.Error = "object expected"
+)
+
+// jsonIndent is the indentation of new keys of the config file.
+const jsonIndent = "    "
+
+// Scope selects the columns to save. The zero value selects the columns of
+// the config.
+type Scope struct {
+	// View is the name of the view.
+	View string
+	// Profile is the name of the profile, it is ignored if the view is set.
+	Profile string
+}
+
+// SaveFields replaces the columns of the scope in the config file. The file
+// is created if it doesn't exist. Only the columns are rewritten, other keys
+// and comments are kept as they are.
+func SaveFields(path string, scope Scope, fields []Field) error {
+	content, err := os.ReadFile(path)
+
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		content = []byte("{}\n")
+	case err != nil:
+		return fmt.Errorf("reading config: %w", err)
+	}
+
+	// Comments are blanked, so values keep their positions in the content.
+	document := blankComments(content)
+
+	object, err := findObject(document, jsonSpan{end: len(document)}, scope)
+	if err != nil {
+		return err
+	}
+
+	content, err = setFields(content, object, fields)
+	if err != nil {
+		return fmt.Errorf("encoding fields: %w", err)
+	}
+
+	if err := atomicfile.WriteFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+
+	return nil
+}
+
+// findObject returns the object of the scope in the document.
+func findObject(document []byte, root jsonSpan, scope Scope) (jsonObject, error) {
+	object, err := decodeObject(document, root)
+	if err != nil {
+		return jsonObject{}, fmt.Errorf("decoding config: %w", err)
+	}
+
+	switch {
+	case scope.View != "":
+		object, err = findNamedObject(document, object, "views", scope.View)
+		if err != nil {
+			return jsonObject{}, fmt.Errorf("view %s: %w", scope.View, err)
+		}
+	case scope.Profile != "":
+		object, err = findNamedObject(document, object, "profiles", scope.Profile)
+		if err != nil {
+			return jsonObject{}, fmt.Errorf("profile %s: %w", scope.Profile, err)
+		}
+	}
+
+	return object, nil
+}
+
+// findNamedObject returns the object with the name in the array of objects
+// by the key, like views.
+func findNamedObject(document []byte, parent jsonObject, key string, name string) (jsonObject, error) {
+	items, ok := parent.member(key)
+	if !ok {
+		return jsonObject{}, ErrNotDeclared
+	}
+
+	elements, err := decodeArray(document, items)
+	if err != nil {
+		return jsonObject{}, fmt.Errorf("decoding %s: %w", key, err)
+	}
+
+	for _, element := range elements {
+		object, err := decodeObject(document, element)
+		if err != nil {
+			return jsonObject{}, fmt.Errorf("decoding %s: %w", key, err)
+		}
+
+		nameSpan, ok := object.member("name")
+		if !ok {
+			continue
+		}
+
+		var itemName string
+
+		if err := json.Unmarshal(nameSpan.bytes(document), &itemName); err == nil && itemName == name {
+			return object, nil
+		}
+	}
+
+	return jsonObject{}, ErrNotDeclared
+}
+
+// setFields replaces the value of "fields" of the object in the content or
+// adds the key after the last one.
+func setFields(content []byte, object jsonObject, fields []Field) ([]byte, error) {
+	if span, ok := object.member("fields"); ok {
+		value, err := json.MarshalIndent(fields, lineIndent(content, span.start), jsonIndent)
+		if err != nil {
+			return nil, err
+		}
+
+		return splice(content, span.start, span.end, value), nil
+	}
+
+	if len(object.members) == 0 {
+		indent := lineIndent(content, object.span.start)
+
+		value, err := json.MarshalIndent(fields, indent+jsonIndent, jsonIndent)
+		if err != nil {
+			return nil, err
+		}
+
+		member := "\n" + indent + jsonIndent + `"fields": ` + string(value) + "\n" + indent
+
+		return splice(content, object.span.start+1, object.span.start+1, []byte(member)), nil
+	}
+
+	last := object.members[len(object.members)-1].value
+	indent := lineIndent(content, last.start)
+
+	value, err := json.MarshalIndent(fields, indent, jsonIndent)
+	if err != nil {
+		return nil, err
+	}
+
+	// The key follows comments of the last value.
+	insertAt := object.span.end - 1
+	for insertAt > last.end && isSpace(content[insertAt-1]) {
+		insertAt--
+	}
+
+	content = splice(content, insertAt, insertAt, []byte("\n"+indent+`"fields": `+string(value)))
+
+	return splice(content, last.end, last.end, []byte(",")), nil
+}
+
+// jsonSpan is a position of a JSON value in the content.
+type jsonSpan struct {
+	start int
+	end   int
+}
+
+func (s jsonSpan) bytes(content []byte) []byte {
+	return content[s.start:s.end]
+}
+
+// jsonMember is a key of an object and the position of its value.
+type jsonMember struct {
+	key   string
+	value jsonSpan
+}
+
+// jsonObject is an object and positions of its values.
+type jsonObject struct {
+	span    jsonSpan
+	members []jsonMember
+}
+
+func (o jsonObject) member(key string) (jsonSpan, bool) {
+	for _, member := range o.members {
+		if member.key == key {
+			return member.value, true
+		}
+	}
+
+	return jsonSpan{}, false
+}
+
+// decodeObject finds positions of values of the object in the span of the
+// document.
+func decodeObject(document []byte, span jsonSpan) (jsonObject, error) {
+	decoder := json.NewDecoder(bytes.NewReader(span.bytes(document)))
+
+	token, err := decoder.Token()
+	if err != nil {
+		return jsonObject{}, err
+	}
+
+	if token != json.Delim('{') {
+		return jsonObject{}, errObjectExpected
+	}
+
+	object := jsonObject{span: jsonSpan{start: span.start + int(decoder.InputOffset()) - 1}}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return jsonObject{}, err
+		}
+
+		key, _ := token.(string)
+
+		var value json.RawMessage
+
+		if err := decoder.Decode(&value); err != nil {
+			return jsonObject{}, fmt.Errorf("%s: %w", key, err)
+		}
+
+		end := span.start + int(decoder.InputOffset())
+
+		object.members = append(object.members, jsonMember{
+			key:   key,
+			value: jsonSpan{start: end - len(value), end: end},
+		})
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return jsonObject{}, err
+	}
+
+	object.span.end = span.start + int(decoder.InputOffset())
+
+	return object, nil
+}
+
+// decodeArray finds positions of elements of the array in the span of the
+// document.
+func decodeArray(document []byte, span jsonSpan) ([]jsonSpan, error) {
+	decoder := json.NewDecoder(bytes.NewReader(span.bytes(document)))
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	var elements []jsonSpan
+
+	for decoder.More() {
+		var value json.RawMessage
+
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		end := span.start + int(decoder.InputOffset())
+		elements = append(elements, jsonSpan{start: end - len(value), end: end})
+	}
+
+	return elements, nil
+}
+
+// blankComments replaces comments of JSONC with spaces, line breaks are
+// kept.
+func blankComments(content []byte) []byte {
+	result := bytes.Clone(content)
+
+	for i := 0; i < len(result); i++ {
+		switch {
+		case result[i] == '"':
+			for i++; i < len(result) && result[i] != '"'; i++ {
+				if result[i] == '\\' {
+					i++
+				}
+			}
+		case bytes.HasPrefix(result[i:], []byte("//")):
+			for ; i < len(result) && result[i] != '\n'; i++ {
+				result[i] = ' '
+			}
+		case bytes.HasPrefix(result[i:], []byte("/*")):
+			end := bytes.Index(result[i+2:], []byte("*/"))
+			if end < 0 {
+				end = len(result)
+			} else {
+				end += i + 4
+			}
+
+			for ; i < end; i++ {
+				if result[i] != '\n' {
+					result[i] = ' '
+				}
+			}
+
+			i--
+		}
+	}
+
+	return result
+}
+
+// lineIndent returns the leading whitespace of the line at the offset.
+func lineIndent(content []byte, offset int) string {
+	start := bytes.LastIndexByte(content[:offset], '\n') + 1
+	end := start
+
+	for end < offset && (content[end] == ' ' || content[end] == '\t') {
+		end++
+	}
+
+	return string(content[start:end])
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+func splice(content []byte, start int, end int, value []byte) []byte {
+	result := make([]byte, 0, len(content)-(end-start)+len(value))
+	result = append(result, content[:start]...)
+	result = append(result, value...)
+
+	return append(result, content[end:]...)
+}
@@ -0,0 +1,157 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/tests"
+)
+
+func TestSaveFields(t *testing.T) {
+	t.Parallel()
+
+	const content = `{
+	// Comments are kept.
+	"timeLayouts": ["2006-01-02"],
+	"views": [{"name": "errors", "minLevel": "error"}],
+	"profiles": [{
+		"name": "k8s",
+		"match": {"keys": ["kubernetes"]},
+		"fields": [{"title": "Pod", "kind": "any", "ref": ["$.kubernetes.pod"]}]
+	}],
+	"isReverseDefault": false // The newest entry is the last.
+}`
+
+	fields := []config.Field{{
+		Title:      "User",
+		Kind:       config.FieldKindAny,
+		References: []string{"$.user.id"},
+		Width:      12,
+	}}
+
+	t.Run("config", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := tests.RequireCreateFile(t, []byte(content))
+
+		require.NoError(t, config.SaveFields(configPath, config.Scope{}, fields))
+
+		saved, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+
+		// Existing keys keep their order, new keys are appended.
+		savedText := string(saved)
+		assert.Less(t, strings.Index(savedText, "timeLayouts"), strings.Index(savedText, "views"))
+		assert.Less(t, strings.Index(savedText, "isReverseDefault"), strings.Index(savedText, "\n\t\"fields\""))
+		assert.Contains(t, savedText, "// Comments are kept.")
+		assert.Contains(t, savedText, `"isReverseDefault": false, // The newest entry is the last.`)
+
+		cfg, err := config.Read(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, fields, cfg.Fields)
+		assert.Equal(t, []string{"2006-01-02"}, cfg.TimeLayouts)
+	})
+
+	t.Run("view", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := tests.RequireCreateFile(t, []byte(content))
+
+		require.NoError(t, config.SaveFields(configPath, config.Scope{View: "errors"}, fields))
+
+		cfg, err := config.Read(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, config.GetDefaultConfig().Fields, cfg.Fields)
+
+		view, ok := cfg.View("errors")
+		require.True(t, ok)
+		assert.Equal(t, fields, view.Fields)
+		assert.Equal(t, "error", view.MinLevel)
+
+		// Saved fields are replaced.
+		require.NoError(t, config.SaveFields(configPath, config.Scope{View: "errors"}, fields[:0]))
+
+		cfg, err = config.Read(configPath)
+		require.NoError(t, err)
+
+		view, ok = cfg.View("errors")
+		require.True(t, ok)
+		assert.Empty(t, view.Fields)
+	})
+
+	t.Run("profile", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := tests.RequireCreateFile(t, []byte(content))
+
+		require.NoError(t, config.SaveFields(configPath, config.Scope{Profile: "k8s"}, fields))
+
+		cfg, err := config.Read(configPath)
+		require.NoError(t, err)
+		require.Len(t, cfg.Profiles, 1)
+		assert.Equal(t, fields, cfg.Profiles[0].Fields)
+		assert.Equal(t, []string{"kubernetes"}, cfg.Profiles[0].Match.Keys)
+	})
+
+	t.Run("not_declared", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := tests.RequireCreateFile(t, []byte(content))
+
+		err := config.SaveFields(configPath, config.Scope{View: "missing"}, fields)
+		require.ErrorIs(t, err, config.ErrNotDeclared)
+
+		saved, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(saved))
+	})
+
+	t.Run("mode", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := tests.RequireCreateFile(t, []byte(content))
+		require.NoError(t, os.Chmod(configPath, 0o640))
+
+		require.NoError(t, config.SaveFields(configPath, config.Scope{}, fields))
+
+		info, err := os.Stat(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+	})
+
+	t.Run("symlink", func(t *testing.T) {
+		t.Parallel()
+
+		target := tests.RequireCreateFile(t, []byte(content))
+		configPath := filepath.Join(t.TempDir(), config.FileName)
+		require.NoError(t, os.Symlink(target, configPath))
+
+		require.NoError(t, config.SaveFields(configPath, config.Scope{}, fields))
+
+		info, err := os.Lstat(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, os.ModeSymlink, info.Mode().Type())
+
+		cfg, err := config.Read(target)
+		require.NoError(t, err)
+		assert.Equal(t, fields, cfg.Fields)
+	})
+
+	t.Run("new_file", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := filepath.Join(t.TempDir(), config.FileName)
+
+		require.NoError(t, config.SaveFields(configPath, config.Scope{}, fields))
+
+		cfg, err := config.Read(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, fields, cfg.Fields)
+	})
+}
@@ -0,0 +1,58 @@
+package config
+
+// Names of built-in themes.
+const (
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+)
+
+// Theme describes colors of the application. Colors are ANSI codes from 0
+// to 255 or hex codes, like "#FF0000". They override colors of the built-in
+// theme, unset colors are taken from it.
+type Theme struct {
+	// Name of the built-in theme, it is "dark" by default.
+	Name string `json:"name,omitempty" validate:"omitempty,oneof=dark light high-contrast"`
+	// Levels are colors of levels by their names, like "error".
+	Levels map[string]string `json:"levels,omitempty" validate:"dive,hexcolor|numeric"`
+	// Border is the color of borders of the table and of the preview.
+	Border string `json:"border,omitempty" validate:"omitempty,hexcolor|numeric"`
+	// Header is the style of the header of the table.
+	Header ThemeStyle `json:"header,omitzero"`
+	// Selected is the style of the selected row.
+	Selected ThemeStyle `json:"selected,omitzero"`
+	// Footer is the style of the status bar that is shown with the full help.
+	Footer ThemeStyle `json:"footer,omitzero"`
+	// JSON is the ID of the theme of the JSON viewer: "1" colors JSON for
+	// dark backgrounds, "0" disables colors.
+	JSON string `json:"json,omitempty" validate:"omitempty,oneof=0 1"`
+}
+
+// ThemeStyle is a style of a part of the application.
+type ThemeStyle struct {
+	Foreground string `json:"foreground,omitempty" validate:"omitempty,hexcolor|numeric"`
+	Background string `json:"background,omitempty" validate:"omitempty,hexcolor|numeric"`
+	Bold       bool   `json:"bold,omitempty"`
+}
+
+// Highlight is a rule that styles rows or cells of matching entries. All
+// conditions that are set must match.
+type Highlight struct {
+	// Term is a case-insensitive substring or a regular expression wrapped
+	// in slashes, like the filter. It is matched against the value of Field,
+	// or against the whole line if Field is empty.
+	Term string `json:"term,omitempty" validate:"required_without_all=Level Op"`
+	// Level matches entries with the level or a more severe one.
+	Level string `json:"level,omitempty"`
+	// Field is the title of a column or a JSONPath, like "$.duration_ms".
+	Field string `json:"field,omitempty" validate:"required_with=Op"`
+	// Op compares the value of Field with Value. Numbers and times are
+	// compared by their values, other values as text.
+	Op    string `json:"op,omitempty" validate:"omitempty,oneof=== != > >= < <=,required_with=Value"`
+	Value string `json:"value,omitempty"`
+
+	// Column is the title of the styled column, the whole row is styled if
+	// it is empty.
+	Column string     `json:"column,omitempty"`
+	Style  ThemeStyle `json:"style"`
+}
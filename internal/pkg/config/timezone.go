@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TimeZoneLocal is the name of the time zone of the system.
+const TimeZoneLocal = "local"
+
+// locations cache loaded time zones, because they are read from the
+// database on every load.
+var (
+	locationsLock sync.Mutex
+	locations     = map[string]*time.Location{}
+)
+
+// LoadLocation returns the time zone by its name, see Config.TimeZone.
+func LoadLocation(name string) (*time.Location, error) {
+	if strings.EqualFold(name, TimeZoneLocal) {
+		return time.Local, nil
+	}
+
+	locationsLock.Lock()
+	defer locationsLock.Unlock()
+
+	if location, ok := locations[name]; ok {
+		return location, nil
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("loading time zone: %w", err)
+	}
+
+	locations[name] = location
+
+	return location, nil
+}
+
+// Location returns the time zone of the field, or nil if times are not
+// converted. Time zones are validated when the config is read.
+func (c *Config) Location(field Field) *time.Location {
+	name := field.TimeZone
+	if name == "" {
+		name = c.TimeZone
+	}
+
+	if name == "" {
+		return nil
+	}
+
+	location, err := LoadLocation(name)
+	if err != nil {
+		return nil
+	}
+
+	return location
+}
+
+func validateTimeZones(cfg *Config) error {
+	names := []string{cfg.TimeZone}
+
+	for _, field := range cfg.Fields {
+		names = append(names, field.TimeZone)
+	}
+
+	for _, view := range cfg.Views {
+		for _, field := range view.Fields {
+			names = append(names, field.TimeZone)
+		}
+	}
+
+	for _, profile := range cfg.Profiles {
+		for _, field := range profile.Fields {
+			names = append(names, field.TimeZone)
+		}
+	}
+
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+
+		if _, err := LoadLocation(name); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
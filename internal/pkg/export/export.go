@@ -0,0 +1,406 @@
+// Package export writes log entries to a file in different formats.
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"sync/atomic"
+
+	"github.com/hedhyw/semerr/pkg/v1/semerr"
+	"github.com/mattn/go-runewidth"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
+)
+
+const (
+	// ErrUnknownFormat is returned if the name of the format is not supported.
+	ErrUnknownFormat semerr.Error = "unknown format"
+	// ErrFileExists is returned if the file to export to exists.
+	ErrFileExists semerr.Error = "file exists"
+)
+
+// Format of the exported file.
+type Format string
+
+// Possible formats.
+const (
+	// FormatNDJSON writes raw lines of the log, entries that span several
+	// lines or are not JSON lines are written as compact JSON objects.
+	FormatNDJSON Format = "ndjson"
+	// FormatCSV writes rendered columns separated by commas.
+	FormatCSV Format = "csv"
+	// FormatTSV writes rendered columns separated by tabs.
+	FormatTSV Format = "tsv"
+	// FormatMarkdown writes rendered columns as a Markdown table.
+	FormatMarkdown Format = "markdown"
+	// FormatText writes rendered columns aligned by their configured width.
+	FormatText Format = "text"
+)
+
+// ParseFormat returns the format by its name. "raw" is an alias of NDJSON.
+func ParseFormat(name string) (Format, error) {
+	format := Format(strings.ToLower(name))
+
+	switch format {
+	case FormatNDJSON, FormatCSV, FormatTSV, FormatMarkdown, FormatText:
+		return format, nil
+	case "raw":
+		return FormatNDJSON, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownFormat, name)
+	}
+}
+
+// FormatFromPath returns the format by the extension of the file. Unknown
+// extensions are exported as raw lines.
+func FormatFromPath(fileName string) Format {
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".csv":
+		return FormatCSV
+	case ".tsv":
+		return FormatTSV
+	case ".md", ".markdown":
+		return FormatMarkdown
+	default:
+		return FormatNDJSON
+	}
+}
+
+// Entries to export.
+type Entries interface {
+	// Len returns the number of all entries.
+	Len() int
+	// LogEntry getter.
+	LogEntry(cfg *config.Config, i int) source.LogEntry
+}
+
+// Job exports entries. It is safe to read the progress while the job is
+// running.
+type Job struct {
+	Entries Entries
+	Config  *config.Config
+	Format  Format
+	// Reverse writes entries from the last to the first.
+	Reverse bool
+	// Notes of entries by their index in the whole log. If there are notes,
+	// formats with columns get the extra column "Note".
+	Notes map[int]string
+	// StyleCell optionally decorates rendered cells of the text format, for
+	// example, it colorizes them.
+	StyleCell func(entry source.LogEntry, column int, value string) string
+
+	written atomic.Int64
+}
+
+// Written returns the number of already exported entries.
+func (j *Job) Written() int {
+	return int(j.written.Load())
+}
+
+// Total returns the number of entries to export.
+func (j *Job) Total() int {
+	return j.Entries.Len()
+}
+
+// Write exports all entries to the writer. It stops if the context is
+// canceled.
+func (j *Job) Write(ctx context.Context, w io.Writer) error {
+	writeRow, flush := j.newRowWriter(w)
+
+	if err := writeRow(nil); err != nil {
+		return fmt.Errorf("writing header: %w", err)
+	}
+
+	total := j.Total()
+
+	for i := range total {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		index := i
+		if j.Reverse {
+			index = total - 1 - i
+		}
+
+		entry := j.Entries.LogEntry(j.Config, index)
+		if entry.Error != nil {
+			return fmt.Errorf("reading entry %d: %w", index, entry.Error)
+		}
+
+		if err := writeRow(&entry); err != nil {
+			return fmt.Errorf("writing entry %d: %w", index, err)
+		}
+
+		j.written.Add(1)
+	}
+
+	return flush()
+}
+
+// newRowWriter returns a function that writes a single entry, or the header
+// if the entry is nil.
+func (j *Job) newRowWriter(w io.Writer) (writeRow func(entry *source.LogEntry) error, flush func() error) {
+	titles := make([]string, 0, len(j.Config.Fields)+1)
+	for _, f := range j.Config.Fields {
+		titles = append(titles, f.Title)
+	}
+
+	if len(j.Notes) > 0 {
+		titles = append(titles, "Note")
+	}
+
+	noFlush := func() error { return nil }
+
+	switch j.Format {
+	case FormatCSV, FormatTSV:
+		csvWriter := csv.NewWriter(w)
+		if j.Format == FormatTSV {
+			csvWriter.Comma = '\t'
+		}
+
+		writeRow = func(entry *source.LogEntry) error {
+			if entry == nil {
+				return csvWriter.Write(titles)
+			}
+
+			return csvWriter.Write(j.cells(entry))
+		}
+
+		flush = func() error {
+			csvWriter.Flush()
+
+			return csvWriter.Error()
+		}
+
+		return writeRow, flush
+	case FormatMarkdown:
+		writeRow = func(entry *source.LogEntry) error {
+			if entry == nil {
+				separators := make([]string, len(titles))
+				for i := range separators {
+					separators[i] = "---"
+				}
+
+				_, err := io.WriteString(w, markdownRow(titles)+markdownRow(separators))
+
+				return err
+			}
+
+			_, err := io.WriteString(w, markdownRow(j.cells(entry)))
+
+			return err
+		}
+
+		return writeRow, noFlush
+	case FormatText:
+		writeRow = func(entry *source.LogEntry) error {
+			if entry == nil {
+				return nil
+			}
+
+			_, err := io.WriteString(w, j.textRow(*entry))
+
+			return err
+		}
+
+		return writeRow, noFlush
+	default:
+		writeRow = func(entry *source.LogEntry) error {
+			if entry == nil {
+				return nil
+			}
+
+			if _, err := w.Write(ndjsonLine(*entry)); err != nil {
+				return err
+			}
+
+			_, err := io.WriteString(w, "\n")
+
+			return err
+		}
+
+		return writeRow, noFlush
+	}
+}
+
+// textRow pads cells to the width of their columns. Values are not truncated
+// and columns with an automatic width are not padded. Durations, bytes and
+// numbers are aligned to the right.
+func (j *Job) textRow(entry source.LogEntry) string {
+	const separator = "  "
+
+	fields := j.cells(&entry)
+
+	var row strings.Builder
+
+	for i, value := range fields {
+		if i > 0 {
+			row.WriteString(separator)
+		}
+
+		padding := ""
+		if i < len(j.Config.Fields) && i < len(fields)-1 {
+			padding = strings.Repeat(" ", max(j.Config.Fields[i].Width-runewidth.StringWidth(value), 0))
+		}
+
+		if j.StyleCell != nil {
+			value = j.StyleCell(entry, i, value)
+		}
+
+		if i < len(j.Config.Fields) && j.Config.Fields[i].IsNumeric() {
+			row.WriteString(padding + value)
+		} else {
+			row.WriteString(value + padding)
+		}
+	}
+
+	row.WriteString("\n")
+
+	return row.String()
+}
+
+// cells returns rendered columns of the entry and its note.
+func (j *Job) cells(entry *source.LogEntry) []string {
+	cells := trimFields(entry.Fields)
+
+	if len(j.Notes) > 0 {
+		cells = append(cells, j.Notes[entry.Index])
+	}
+
+	return cells
+}
+
+// trimFields removes the trailing line break that fields of plain logs keep.
+func trimFields(fields []string) []string {
+	trimmed := make([]string, len(fields))
+
+	for i, f := range fields {
+		trimmed[i] = strings.TrimRight(f, "\r\n")
+	}
+
+	return trimmed
+}
+
+func markdownRow(cells []string) string {
+	escaper := strings.NewReplacer(
+		"|", `\|`,
+		"\r\n", "<br>",
+		"\n", "<br>",
+	)
+
+	var row strings.Builder
+
+	row.WriteString("|")
+
+	for _, cell := range cells {
+		row.WriteString(" " + escaper.Replace(cell) + " |")
+	}
+
+	row.WriteString("\n")
+
+	return row.String()
+}
+
+// ndjsonLine returns the line of the entry if it is a JSON value already.
+// Otherwise, the JSON object of the entry is compacted, so entries joined
+// from several lines and decoded CSV rows take a single line. The text before
+// an object that is embedded into the line is kept in the PrefixFieldName
+// field. Lines without JSON objects are written as they are.
+func ndjsonLine(entry source.LogEntry) []byte {
+	line := bytes.TrimRight(entry.Line, "\r\n")
+
+	if len(entry.JSON) == 0 || (!bytes.ContainsAny(line, "\r\n") && json.Valid(line)) {
+		return line
+	}
+
+	var compacted bytes.Buffer
+
+	if err := json.Compact(&compacted, entry.JSON); err != nil {
+		return line
+	}
+
+	return withPrefixField(compacted.Bytes(), embeddedPrefix(line, entry.JSON))
+}
+
+// embeddedPrefix returns the text before the object that is embedded into
+// the line. It is empty if the object is not a part of the line.
+func embeddedPrefix(line []byte, object []byte) string {
+	start := bytes.Index(line, object)
+	if start <= 0 {
+		return ""
+	}
+
+	return strings.TrimSpace(string(line[:start]))
+}
+
+// withPrefixField adds the prefix to the compacted object as the first field,
+// unless the object has the field already.
+func withPrefixField(object []byte, prefix string) []byte {
+	if prefix == "" {
+		return object
+	}
+
+	var fields map[string]json.RawMessage
+
+	if err := json.Unmarshal(object, &fields); err != nil {
+		return object
+	}
+
+	if _, ok := fields[source.PrefixFieldName]; ok {
+		return object
+	}
+
+	field, err := json.Marshal(map[string]string{source.PrefixFieldName: prefix})
+	if err != nil {
+		return object
+	}
+
+	if len(fields) == 0 {
+		return field
+	}
+
+	// The closing brace of the field is replaced by the fields of the object.
+	return append(append(field[:len(field)-1], ','), object[1:]...)
+}
+
+// WriteFile exports all entries to a new file. It fails with ErrFileExists
+// if the file exists, so files of the user are never overwritten. The
+// created file is removed if the export fails or it is canceled.
+func (j *Job) WriteFile(ctx context.Context, name string) (err error) {
+	file, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("%w: %s", ErrFileExists, name)
+		}
+
+		return fmt.Errorf("creating file: %w", err)
+	}
+
+	defer func() {
+		err = errors.Join(err, file.Close())
+
+		if err != nil {
+			err = errors.Join(err, os.Remove(name))
+		}
+	}()
+
+	writer := bufio.NewWriter(file)
+
+	if err := j.Write(ctx, writer); err != nil {
+		return err
+	}
+
+	return writer.Flush()
+}
@@ -0,0 +1,388 @@
+package export_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/export"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
+)
+
+const testLog = `{"time":"2026-10-17T10:00:00Z","level":"info","message":"first | pipe"}
+{"time":"2026-10-17T10:00:01Z","level":"error","message":"second, comma"}
+plain text
+`
+
+func TestFormatFromPath(t *testing.T) {
+	t.Parallel()
+
+	testCases := [...]struct {
+		Path     string
+		Expected export.Format
+	}{
+		{Path: "export.csv", Expected: export.FormatCSV},
+		{Path: "EXPORT.TSV", Expected: export.FormatTSV},
+		{Path: "/tmp/export.md", Expected: export.FormatMarkdown},
+		{Path: "export.markdown", Expected: export.FormatMarkdown},
+		{Path: "export.log", Expected: export.FormatNDJSON},
+		{Path: "export", Expected: export.FormatNDJSON},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Path, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.Expected, export.FormatFromPath(testCase.Path))
+		})
+	}
+}
+
+func TestJobWrite(t *testing.T) {
+	t.Parallel()
+
+	testCases := [...]struct {
+		Name     string
+		Format   export.Format
+		Reverse  bool
+		Expected string
+	}{{
+		Name:     "ndjson",
+		Format:   export.FormatNDJSON,
+		Expected: testLog,
+	}, {
+		Name:    "ndjson_reverse",
+		Format:  export.FormatNDJSON,
+		Reverse: true,
+		Expected: `plain text
+{"time":"2026-10-17T10:00:01Z","level":"error","message":"second, comma"}
+{"time":"2026-10-17T10:00:00Z","level":"info","message":"first | pipe"}
+`,
+	}, {
+		Name:   "csv",
+		Format: export.FormatCSV,
+		Expected: `Time,Level,Message
+2026-10-17T10:00:00Z,info,first | pipe
+2026-10-17T10:00:01Z,error,"second, comma"
+-,-,plain text
+`,
+	}, {
+		Name:   "tsv",
+		Format: export.FormatTSV,
+		Expected: "Time\tLevel\tMessage\n" +
+			"2026-10-17T10:00:00Z\tinfo\tfirst | pipe\n" +
+			"2026-10-17T10:00:01Z\terror\tsecond, comma\n" +
+			"-\t-\tplain text\n",
+	}, {
+		Name:   "markdown",
+		Format: export.FormatMarkdown,
+		Expected: `| Time | Level | Message |
+| --- | --- | --- |
+| 2026-10-17T10:00:00Z | info | first \| pipe |
+| 2026-10-17T10:00:01Z | error | second, comma |
+| - | - | plain text |
+`,
+	}, {
+		Name:   "text",
+		Format: export.FormatText,
+		Expected: "2026-10-17T10:00:00Z" + strings.Repeat(" ", 12) + "info" + strings.Repeat(" ", 8) + "first | pipe\n" +
+			"2026-10-17T10:00:01Z" + strings.Repeat(" ", 12) + "error" + strings.Repeat(" ", 7) + "second, comma\n" +
+			"-" + strings.Repeat(" ", 31) + "-" + strings.Repeat(" ", 11) + "plain text\n",
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			job := &export.Job{
+				Entries: requireLogEntries(t, testLog),
+				Config:  config.GetDefaultConfig(),
+				Format:  testCase.Format,
+				Reverse: testCase.Reverse,
+			}
+
+			var buf bytes.Buffer
+
+			require.NoError(t, job.Write(t.Context(), &buf))
+
+			assert.Equal(t, testCase.Expected, buf.String())
+			assert.Equal(t, 3, job.Written())
+			assert.Equal(t, 3, job.Total())
+		})
+	}
+}
+
+func TestJobWriteNDJSONCompact(t *testing.T) {
+	t.Parallel()
+
+	testCases := [...]struct {
+		Name     string
+		Input    string
+		Apply    func(cfg *config.Config)
+		Expected string
+	}{{
+		Name:  "multiline",
+		Input: "{\n  \"message\": \"first\"\n}\n{\"message\": \"second\"}\n",
+		Apply: func(cfg *config.Config) {
+			cfg.Multiline = config.Multiline{JSON: true}
+		},
+		Expected: "{\"message\":\"first\"}\n{\"message\": \"second\"}\n",
+	}, {
+		Name:  "csv",
+		Input: "level,message\ninfo,first\n",
+		Apply: func(cfg *config.Config) {
+			cfg.InputFormat = config.InputFormatCSV
+		},
+		Expected: "{\"level\":\"info\",\"message\":\"first\"}\n",
+	}, {
+		Name:     "prefix",
+		Input:    "2024-01-01 app: {\"message\": \"first\"}\nprefix {}\nkept {\"_prefix\":\"own\"}\n",
+		Apply:    func(*config.Config) {},
+		Expected: "{\"_prefix\":\"2024-01-01 app:\",\"message\":\"first\"}\n{\"_prefix\":\"prefix\"}\n{\"_prefix\":\"own\"}\n",
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.GetDefaultConfig()
+			testCase.Apply(cfg)
+
+			inputSource, err := source.Reader(strings.NewReader(testCase.Input), cfg)
+			require.NoError(t, err)
+
+			t.Cleanup(func() { assert.NoError(t, inputSource.Close()) })
+
+			entries, err := inputSource.ParseLogEntries()
+			require.NoError(t, err)
+
+			job := &export.Job{
+				Entries: entries,
+				Config:  cfg,
+				Format:  export.FormatNDJSON,
+			}
+
+			var buf bytes.Buffer
+
+			require.NoError(t, job.Write(t.Context(), &buf))
+
+			assert.Equal(t, testCase.Expected, buf.String())
+		})
+	}
+}
+
+func TestJobWriteStyleCell(t *testing.T) {
+	t.Parallel()
+
+	job := &export.Job{
+		Entries: requireLogEntries(t, testLog),
+		Config:  config.GetDefaultConfig(),
+		Format:  export.FormatText,
+		StyleCell: func(_ source.LogEntry, column int, value string) string {
+			if column == 1 {
+				return "<" + value + ">"
+			}
+
+			return value
+		},
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, job.Write(t.Context(), &buf))
+
+	lines := strings.Split(buf.String(), "\n")
+	assert.Contains(t, lines[0], "  <info>        first")
+	assert.Contains(t, lines[1], "  <error>       second")
+}
+
+func TestJobWriteAlignNumbers(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	cfg.Fields = []config.Field{
+		{Title: "Count", Kind: config.FieldKindNumber, References: []string{"$.count"}, Width: 8},
+		{Title: "Message", Kind: config.FieldKindMessage, References: []string{"$.message"}},
+	}
+
+	job := &export.Job{
+		Entries: requireLogEntries(t, `{"count":12345,"message":"first"}`+"\n"),
+		Config:  cfg,
+		Format:  export.FormatText,
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, job.Write(t.Context(), &buf))
+
+	assert.Equal(t, "  12,345  first\n", buf.String())
+}
+
+func TestJobWriteNotes(t *testing.T) {
+	t.Parallel()
+
+	job := &export.Job{
+		Entries: requireLogEntries(t, testLog),
+		Config:  config.GetDefaultConfig(),
+		Format:  export.FormatCSV,
+		Notes:   map[int]string{1: "root cause"},
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, job.Write(t.Context(), &buf))
+
+	assert.Equal(t, `Time,Level,Message,Note
+2026-10-17T10:00:00Z,info,first | pipe,
+2026-10-17T10:00:01Z,error,"second, comma",root cause
+-,-,plain text,
+`, buf.String())
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	testCases := [...]struct {
+		Name     string
+		Expected export.Format
+	}{
+		{Name: "text", Expected: export.FormatText},
+		{Name: "raw", Expected: export.FormatNDJSON},
+		{Name: "ndjson", Expected: export.FormatNDJSON},
+		{Name: "CSV", Expected: export.FormatCSV},
+		{Name: "tsv", Expected: export.FormatTSV},
+		{Name: "markdown", Expected: export.FormatMarkdown},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := export.ParseFormat(testCase.Name)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.Expected, actual)
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := export.ParseFormat("xml")
+		require.ErrorIs(t, err, export.ErrUnknownFormat)
+	})
+}
+
+func TestJobWriteCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	job := &export.Job{
+		Entries: requireLogEntries(t, testLog),
+		Config:  config.GetDefaultConfig(),
+		Format:  export.FormatNDJSON,
+	}
+
+	var buf bytes.Buffer
+
+	require.ErrorIs(t, job.Write(ctx, &buf), context.Canceled)
+	assert.Zero(t, job.Written())
+}
+
+func TestJobWriteFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		name := filepath.Join(t.TempDir(), "export.log")
+
+		job := &export.Job{
+			Entries: requireLogEntries(t, testLog),
+			Config:  config.GetDefaultConfig(),
+			Format:  export.FormatNDJSON,
+		}
+
+		require.NoError(t, job.WriteFile(t.Context(), name))
+
+		content, err := os.ReadFile(name)
+		require.NoError(t, err)
+
+		assert.Equal(t, testLog, string(content))
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		name := filepath.Join(t.TempDir(), "export.log")
+
+		job := &export.Job{
+			Entries: requireLogEntries(t, testLog),
+			Config:  config.GetDefaultConfig(),
+			Format:  export.FormatNDJSON,
+		}
+
+		require.ErrorIs(t, job.WriteFile(ctx, name), context.Canceled)
+
+		_, err := os.Stat(name)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("file_exists", func(t *testing.T) {
+		t.Parallel()
+
+		name := filepath.Join(t.TempDir(), "export.log")
+		require.NoError(t, os.WriteFile(name, []byte("original"), 0o600))
+
+		job := &export.Job{
+			Entries: requireLogEntries(t, testLog),
+			Config:  config.GetDefaultConfig(),
+			Format:  export.FormatNDJSON,
+		}
+
+		require.ErrorIs(t, job.WriteFile(t.Context(), name), export.ErrFileExists)
+
+		// The file is neither overwritten nor removed.
+		content, err := os.ReadFile(name)
+		require.NoError(t, err)
+		assert.Equal(t, "original", string(content))
+	})
+
+	t.Run("directory_not_found", func(t *testing.T) {
+		t.Parallel()
+
+		job := &export.Job{
+			Entries: requireLogEntries(t, testLog),
+			Config:  config.GetDefaultConfig(),
+			Format:  export.FormatNDJSON,
+		}
+
+		err := job.WriteFile(t.Context(), filepath.Join(t.TempDir(), "not_found", "export.log"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func requireLogEntries(tb testing.TB, content string) source.LazyLogEntries {
+	tb.Helper()
+
+	inputSource, err := source.Reader(strings.NewReader(content), config.GetDefaultConfig())
+	require.NoError(tb, err)
+
+	tb.Cleanup(func() { assert.NoError(tb, inputSource.Close()) })
+
+	logEntries, err := inputSource.ParseLogEntries()
+	require.NoError(tb, err)
+
+	return logEntries
+}
@@ -0,0 +1,130 @@
+// Package session persists the state of the viewer per log file, so that it
+// is restored when the same file is opened again.
+package session
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/atomicfile"
+)
+
+// headSize limits the first line of the file that identifies it. The rest of
+// the file is not hashed, so the session survives appended lines, but not
+// a rotated file.
+const headSize = 4096
+
+// State of the viewer. Entries are referenced by their index in the whole
+// log.
+type State struct {
+	FilterText  string `json:"filterText,omitempty"`
+	FilterField string `json:"filterField,omitempty"`
+	MarkedOnly  bool   `json:"markedOnly,omitempty"`
+	MinLevel    string `json:"minLevel,omitempty"`
+	// View is the name of the view of the config.
+	View    string `json:"view,omitempty"`
+	Reverse bool   `json:"reverse"`
+	// Cursor is the index of the selected entry, it is -1 if the viewer
+	// follows the end of the log.
+	Cursor int            `json:"cursor"`
+	Marks  []int          `json:"marks,omitempty"`
+	Notes  map[int]string `json:"notes,omitempty"`
+}
+
+// Store keeps states in files of the directory.
+type Store struct {
+	Dir string
+}
+
+// DefaultDir returns the directory of sessions in the user cache directory.
+func DefaultDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("getting cache dir: %w", err)
+	}
+
+	return filepath.Join(cacheDir, "jlv", "sessions"), nil
+}
+
+// Key identifies the log file by its absolute path and the hash of its
+// first line.
+func Key(fileName string) (string, error) {
+	absPath, err := filepath.Abs(fileName)
+	if err != nil {
+		return "", fmt.Errorf("getting absolute path: %w", err)
+	}
+
+	file, err := os.Open(absPath)
+	if err != nil {
+		return "", fmt.Errorf("opening: %w", err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	head := make([]byte, headSize)
+
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", fmt.Errorf("reading: %w", err)
+	}
+
+	head, _, _ = bytes.Cut(head[:n], []byte("\n"))
+
+	hash := sha256.New()
+	hash.Write([]byte(absPath))
+	hash.Write([]byte{0})
+	hash.Write(head)
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Load returns the saved state. It returns false if there is no state.
+func (s Store) Load(key string) (State, bool, error) {
+	content, err := os.ReadFile(s.path(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return State{}, false, nil
+		}
+
+		return State{}, false, fmt.Errorf("reading: %w", err)
+	}
+
+	var state State
+
+	if err := json.Unmarshal(content, &state); err != nil {
+		return State{}, false, fmt.Errorf("decoding: %w", err)
+	}
+
+	return state, true, nil
+}
+
+// Save writes the state. The file is replaced atomically, so a concurrent
+// viewer never reads a partial state.
+func (s Store) Save(key string, state State) error {
+	content, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encoding: %w", err)
+	}
+
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return fmt.Errorf("creating dir: %w", err)
+	}
+
+	if err := atomicfile.WriteFile(s.path(key), content, 0o600); err != nil {
+		return fmt.Errorf("writing: %w", err)
+	}
+
+	return nil
+}
+
+func (s Store) path(key string) string {
+	return filepath.Join(s.Dir, key+".json")
+}
@@ -0,0 +1,90 @@
+package session_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/session"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/tests"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	store := session.Store{Dir: filepath.Join(t.TempDir(), "sessions")}
+
+	_, ok, err := store.Load("key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	expected := session.State{
+		FilterText:  "error",
+		FilterField: "Level",
+		MarkedOnly:  true,
+		Reverse:     true,
+		Cursor:      2,
+		Marks:       []int{1, 2},
+		Notes:       map[int]string{2: "root cause"},
+	}
+
+	require.NoError(t, store.Save("key", expected))
+
+	actual, ok, err := store.Load("key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, expected, actual)
+
+	files, err := os.ReadDir(store.Dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestStoreLoadInvalid(t *testing.T) {
+	t.Parallel()
+
+	store := session.Store{Dir: t.TempDir()}
+
+	require.NoError(t, os.WriteFile(filepath.Join(store.Dir, "key.json"), []byte("-"), 0o600))
+
+	_, _, err := store.Load("key")
+	require.Error(t, err)
+}
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	fileName := tests.RequireCreateFile(t, []byte("first\n"))
+
+	key, err := session.Key(fileName)
+	require.NoError(t, err)
+
+	otherFileName := tests.RequireCreateFile(t, []byte("first\n"))
+
+	otherKey, err := session.Key(otherFileName)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, otherKey, "different paths")
+
+	file, err := os.OpenFile(fileName, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+
+	_, err = file.WriteString("second\n")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	appendedKey, err := session.Key(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, key, appendedKey, "appended")
+
+	require.NoError(t, os.WriteFile(fileName, []byte("rotated\n"), 0o600))
+
+	rotatedKey, err := session.Key(fileName)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, rotatedKey, "rotated")
+
+	_, err = session.Key(fileName + "_not_found")
+	require.Error(t, err)
+}
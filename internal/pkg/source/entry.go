@@ -1,11 +1,15 @@
 package source
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -21,13 +25,23 @@ const (
 	unitSeconds = "s"
 	unitMilli   = "ms"
 	unitMicro   = "us"
+	unitNano    = "ns"
 )
 
+// PrefixFieldName is a synthetic field of a line with embedded JSON. It holds
+// the text before the JSON object, for example, the timestamp and the stream
+// added by docker or kubernetes: `2006-01-02T15:04:05Z stdout F {...}`.
+const PrefixFieldName = "_prefix"
+
 // LazyLogEntry holds unredenred LogEntry. Use `LogEntry` getter.
 type LazyLogEntry struct {
 	offset int64
 	length int
 	index  int
+
+	// decoder converts records of the CSV and TSV formats to JSON. It is nil
+	// for other formats.
+	decoder *csvDecoder
 }
 
 // Length of the entry.
@@ -35,6 +49,11 @@ func (e LazyLogEntry) Length() int {
 	return e.length
 }
 
+// Index of the entry in the whole log. It doesn't change after filtering.
+func (e LazyLogEntry) Index() int {
+	return e.index
+}
+
 // Line re-reads the line.
 func (e LazyLogEntry) Line(file *os.File) (json.RawMessage, error) {
 	data := make([]byte, e.length)
@@ -57,8 +76,24 @@ func (e LazyLogEntry) LogEntry(file *os.File, cfg *config.Config) LogEntry {
 		}
 	}
 
-	entry := parseLogEntry(line, cfg)
+	if e.decoder == nil {
+		entry := parseLogEntry(line, cfg)
+		entry.Index = e.index
+
+		return entry
+	}
+
+	decoded, err := e.decoder.decode(line)
+	if err != nil {
+		return LogEntry{
+			Index: e.index,
+			Error: err,
+		}
+	}
+
+	entry := parseLogEntry(decoded, cfg)
 	entry.Index = e.index
+	entry.Line = line
 
 	return entry
 }
@@ -69,6 +104,20 @@ type LogEntry struct {
 	Fields []string
 	Line   json.RawMessage
 	Error  error
+
+	// JSON is the object found in the line. It differs from the line if the
+	// object is embedded after a text prefix, and it is empty for plain logs.
+	JSON json.RawMessage
+}
+
+// Content returns the JSON object of the entry if it has one, otherwise it
+// returns the raw line.
+func (e LogEntry) Content() json.RawMessage {
+	if len(e.JSON) != 0 {
+		return e.JSON
+	}
+
+	return e.Line
 }
 
 // Row returns table.Row representation of the log entry.
@@ -80,6 +129,9 @@ func (e LogEntry) Row() table.Row {
 type LazyLogEntries struct {
 	Seeker  *os.File
 	Entries []LazyLogEntry
+	// Loaded is true if the end of the input has been reached. Entries
+	// that are appended to a followed file are received later.
+	Loaded bool
 }
 
 // Row returns table.Row representation of the log entry.
@@ -101,7 +153,9 @@ func (entries LazyLogEntries) Len() int {
 // wrapped in slashes (/.../) is matched as a regular expression instead.
 //
 // In fulltext mode the term is matched against the whole raw JSON line, in
-// field mode against the rendered value of the given field.
+// field mode against the rendered value of the given field. Values of
+// duration, bytes, number and bool fields are compared if the term starts
+// with an operator, like ">=1.5s".
 func (entries LazyLogEntries) Filter(term string, fieldName string, c *config.Config) (LazyLogEntries, error) {
 	if term == "" {
 		return entries, nil
@@ -117,6 +171,10 @@ func (entries LazyLogEntries) Filter(term string, fieldName string, c *config.Co
 		return LazyLogEntries{}, err
 	}
 
+	// Terms like ">1s" compare values of durations, bytes, numbers and
+	// booleans.
+	compare, isComparison := newComparisonMatcher(term, fieldName, c)
+
 	filtered := make([]LazyLogEntry, 0, len(entries.Entries))
 
 	for _, f := range entries.Entries {
@@ -138,6 +196,14 @@ func (entries LazyLogEntries) Filter(term string, fieldName string, c *config.Co
 				return LazyLogEntries{}, entry.Error
 			}
 
+			if isComparison {
+				if compare(entry) {
+					filtered = append(filtered, f)
+				}
+
+				continue
+			}
+
 			// A field of a non-JSON line holds the untouched line, which
 			// still keeps its trailing line break, so it is trimmed too.
 			value = bytes.TrimRight([]byte(entry.Fields[fieldIndex]), "\r\n")
@@ -154,6 +220,60 @@ func (entries LazyLogEntries) Filter(term string, fieldName string, c *config.Co
 	}, nil
 }
 
+// FilterByIndex returns entries whose index in the whole log is accepted by
+// the keep function.
+func (entries LazyLogEntries) FilterByIndex(keep func(index int) bool) LazyLogEntries {
+	filtered := make([]LazyLogEntry, 0, len(entries.Entries))
+
+	for _, f := range entries.Entries {
+		if keep(f.index) {
+			filtered = append(filtered, f)
+		}
+	}
+
+	return LazyLogEntries{
+		Seeker:  entries.Seeker,
+		Entries: filtered,
+	}
+}
+
+// FilterByLevel returns entries with levels that are as severe as the minimum
+// level or more. Entries without a level are excluded.
+func (entries LazyLogEntries) FilterByLevel(minimum Level, c *config.Config) (LazyLogEntries, error) {
+	levelIndex := -1
+
+	for i := 0; c != nil && i < len(c.Fields); i++ {
+		if c.Fields[i].Kind == config.FieldKindLevel {
+			levelIndex = i
+
+			break
+		}
+	}
+
+	if levelIndex < 0 {
+		return LazyLogEntries{}, fmt.Errorf("%w: no level column", ErrInvalidFilter)
+	}
+
+	filtered := make([]LazyLogEntry, 0, len(entries.Entries))
+
+	for _, f := range entries.Entries {
+		entry := f.LogEntry(entries.Seeker, c)
+		if entry.Error != nil {
+			return LazyLogEntries{}, entry.Error
+		}
+
+		// The column holds the parsed level.
+		if Level(entry.Fields[levelIndex]).AtLeast(minimum, c) {
+			filtered = append(filtered, f)
+		}
+	}
+
+	return LazyLogEntries{
+		Seeker:  entries.Seeker,
+		Entries: filtered,
+	}, nil
+}
+
 // NewMatcher returns a case-insensitive predicate for the given term. A term
 // wrapped in slashes (/.../) is compiled as a regular expression, otherwise
 // a substring match is used.
@@ -208,12 +328,25 @@ func parseField(
 	field config.Field,
 	cfg *config.Config,
 ) string {
+	if field.Template != "" {
+		value, ok := executeTemplate(parsedLine, field.Template)
+		if !ok || strings.TrimSpace(value) == "" {
+			return "-"
+		}
+
+		return formatField(value, field, cfg)
+	}
+
 	for _, ref := range field.References {
 		foundField, err := jsonpath.Read(parsedLine, ref)
 		if err != nil {
 			continue
 		}
 
+		if field.Kind == config.FieldKindJSON {
+			return formatMessage(formatJSON(foundField))
+		}
+
 		jsonField, err := json.Marshal(foundField)
 		if err != nil {
 			return fmt.Sprint(field)
@@ -248,6 +381,8 @@ func formatField(
 		timeFormat = *field.TimeFormat
 	}
 
+	location := cfg.Location(field)
+
 	// Numeric time attempts to infer the duration based on the length of the string
 	if kind == config.FieldKindNumericTime {
 		kind = guessTimeFieldKind(value)
@@ -257,15 +392,20 @@ func formatField(
 	case config.FieldKindMessage:
 		return formatMessage(value)
 	case config.FieldKindLevel:
-		return string(ParseLevel(formatMessage(value), cfg.CustomLevelMapping))
+		return string(ParseConfigLevel(formatMessage(value), cfg))
 	case config.FieldKindTime:
-		return formatMessage(reformatTime(value, cfg.TimeLayouts, timeFormat))
+		return formatMessage(reformatTime(value, cfg.TimeLayouts, timeFormat, location))
 	case config.FieldKindSecondTime:
-		return formatMessage(formatTimeValue(value, unitSeconds, timeFormat))
+		return formatMessage(formatTimeValue(value, unitSeconds, timeFormat, location))
 	case config.FieldKindMilliTime:
-		return formatMessage(formatTimeValue(value, unitMilli, timeFormat))
+		return formatMessage(formatTimeValue(value, unitMilli, timeFormat, location))
 	case config.FieldKindMicroTime:
-		return formatMessage(formatTimeValue(value, unitMicro, timeFormat))
+		return formatMessage(formatTimeValue(value, unitMicro, timeFormat, location))
+	case config.FieldKindNanoTime:
+		return formatMessage(formatTimeValue(value, unitNano, timeFormat, location))
+	case config.FieldKindDuration, config.FieldKindBytes, config.FieldKindNumber,
+		config.FieldKindBool, config.FieldKindJSON:
+		return formatMessage(formatValue(value, field))
 	case config.FieldKindAny:
 		return formatMessage(value)
 	default:
@@ -273,10 +413,16 @@ func formatField(
 	}
 }
 
-func reformatTime(value string, layoutsToReformat []string, timeFormat string) string {
+// reformatTime formats the time in the location, the parsed zone is kept if
+// the location is nil.
+func reformatTime(value string, layoutsToReformat []string, timeFormat string, location *time.Location) string {
 	for _, laoyout := range layoutsToReformat {
 		parsed, err := time.Parse(laoyout, value)
 		if err == nil {
+			if location != nil {
+				parsed = parsed.In(location)
+			}
+
 			return parsed.Format(timeFormat)
 		}
 	}
@@ -289,29 +435,268 @@ func parseLogEntry(
 	line json.RawMessage,
 	cfg *config.Config,
 ) LogEntry {
-	var parsedLine any
-
-	err := json.Unmarshal(normalizeJSON(line), &parsedLine)
-	if err != nil {
+	parsedLine, jsonObject, ok := parseJSONObject(normalizeJSON(line))
+	if !ok {
 		return getPlainLogEntry(line, cfg)
 	}
 
-	if _, ok := parsedLine.(map[string]any); !ok {
-		return getPlainLogEntry(line, cfg)
+	if unwrapFields(parsedLine, cfg.Unwrap) {
+		// The expanded view shows the decoded object.
+		if marshaled, err := json.Marshal(parsedLine); err == nil {
+			jsonObject = marshaled
+		}
 	}
 
-	fields := make([]string, 0, len(cfg.Fields))
+	columns := cfg.Fields
+	entryCfg := cfg
 
-	for _, f := range cfg.Fields {
-		fields = append(fields, parseField(parsedLine, f, cfg))
+	// Entries of merged logs can have different shapes, each of them is
+	// parsed by its own profile. A log with the selected profile has
+	// a single shape, so entries are not matched.
+	if cfg.Profile == "" && len(cfg.Profiles) > 0 {
+		if profile, ok := matchProfile(parsedLine, cfg.Profiles); ok {
+			columns = profileColumns(cfg.Fields, profile.Fields)
+			entryCfg = cfg.ProfileConfig(profile)
+		}
+	}
+
+	fields := make([]string, 0, len(columns))
+
+	for _, f := range columns {
+		fields = append(fields, parseField(parsedLine, f, entryCfg))
 	}
 
 	return LogEntry{
 		Line:   line,
+		JSON:   jsonObject,
 		Fields: fields,
 	}
 }
 
+// matchProfile returns the first profile, which keys all exist in the line.
+func matchProfile(parsedLine map[string]any, profiles []config.Profile) (config.Profile, bool) {
+	for _, profile := range profiles {
+		if len(profile.Match.Keys) == 0 {
+			continue
+		}
+
+		if !slices.ContainsFunc(profile.Match.Keys, func(ref string) bool {
+			_, err := jsonpath.Read(parsedLine, ref)
+
+			return err != nil
+		}) {
+			return profile, true
+		}
+	}
+
+	return config.Profile{}, false
+}
+
+// profileColumns returns fields of the profile in the order of the columns.
+// Fields are matched by their titles, columns that are missing in the profile
+// are left empty.
+func profileColumns(columns []config.Field, profileFields []config.Field) []config.Field {
+	result := make([]config.Field, len(columns))
+
+	for i, column := range columns {
+		index := slices.IndexFunc(profileFields, func(f config.Field) bool {
+			return strings.EqualFold(f.Title, column.Title)
+		})
+
+		if index >= 0 {
+			result[i] = profileFields[index]
+		} else {
+			result[i] = config.Field{Title: column.Title, Kind: column.Kind}
+		}
+	}
+
+	return result
+}
+
+// DetectProfile returns the profile that matches the first JSON entry of the
+// log by keys.
+func DetectProfile(r io.Reader, cfg *config.Config) (config.Profile, bool) {
+	const maxLines = 100
+
+	reader := bufio.NewReader(r)
+
+	for range maxLines {
+		line, err := reader.ReadBytes('\n')
+
+		if parsedLine, _, ok := parseJSONObject(normalizeJSON(line)); ok {
+			return matchProfile(parsedLine, cfg.Profiles)
+		}
+
+		if err != nil {
+			break
+		}
+	}
+
+	return config.Profile{}, false
+}
+
+// unwrapFields decodes fields that hold JSON encoded as a string. It returns
+// true if any field has been decoded.
+func unwrapFields(parsedLine map[string]any, unwraps []config.Unwrap) bool {
+	changed := false
+
+	for _, unwrap := range unwraps {
+		text, ok := parsedLine[unwrap.Field].(string)
+		if !ok {
+			continue
+		}
+
+		var decoded any
+
+		if err := unmarshalJSON([]byte(text), &decoded); err != nil {
+			continue
+		}
+
+		switch decoded := decoded.(type) {
+		case map[string]any:
+			if !unwrap.Merge {
+				parsedLine[unwrap.Field] = decoded
+
+				break
+			}
+
+			delete(parsedLine, unwrap.Field)
+
+			// Keys of the decoded object take precedence over the keys of
+			// the wrapper, which usually holds only metadata.
+			for key, value := range decoded {
+				parsedLine[key] = value
+			}
+		case []any:
+			parsedLine[unwrap.Field] = decoded
+		default:
+			// Strings, numbers and other scalar values are kept as is.
+			continue
+		}
+
+		changed = true
+	}
+
+	return changed
+}
+
+// parseJSONObject parses the line as a JSON object. If the line is not a JSON
+// object, it looks for the first object embedded into the line and keeps the
+// text before it in the synthetic field PrefixFieldName. The text after the
+// object is ignored.
+func parseJSONObject(line []byte) (map[string]any, json.RawMessage, bool) {
+	var parsedLine map[string]any
+
+	if err := unmarshalJSON(line, &parsedLine); err == nil {
+		return parsedLine, line, parsedLine != nil
+	}
+
+	// Other JSON values, like arrays, are not searched for embedded objects.
+	if json.Valid(line) {
+		return nil, nil, false
+	}
+
+	start, end, ok := findEmbeddedObject(line)
+	if !ok {
+		return nil, nil, false
+	}
+
+	parsedLine = nil
+
+	if err := unmarshalJSON(line[start:end], &parsedLine); err != nil || parsedLine == nil {
+		return nil, nil, false
+	}
+
+	prefix := strings.TrimSpace(string(line[:start]))
+	if _, ok := parsedLine[PrefixFieldName]; !ok && prefix != "" {
+		parsedLine[PrefixFieldName] = prefix
+	}
+
+	return parsedLine, line[start:end], true
+}
+
+// findEmbeddedObject returns bounds of the first JSON object that is embedded
+// into the line. Braces are matched in one pass, braces inside strings are
+// skipped, so invalid candidates before the object don't hide it and plain
+// lines with many braces are not decoded over and over.
+func findEmbeddedObject(line []byte) (start, end int, ok bool) {
+	var (
+		starts   []int
+		inString bool
+		escaped  bool
+	)
+
+	for i, c := range line {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+
+			continue
+		}
+
+		switch c {
+		case '"':
+			// Quotes outside of braces belong to the prefix, they don't
+			// start strings.
+			inString = len(starts) > 0
+		case '{':
+			starts = append(starts, i)
+		case '}':
+			if len(starts) == 0 {
+				continue
+			}
+
+			candidate := starts[len(starts)-1]
+			starts = starts[:len(starts)-1]
+
+			// An outer object contains the inner ones, so it starts before
+			// them and replaces them.
+			if (!ok || candidate < start) && isObjectStart(line[candidate:]) && json.Valid(line[candidate:i+1]) {
+				start, end, ok = candidate, i+1, true
+			}
+
+			// Objects that follow can't start before the found one.
+			if ok && len(starts) == 0 {
+				return start, end, true
+			}
+		}
+	}
+
+	return start, end, ok
+}
+
+// unmarshalJSON decodes the JSON value like json.Unmarshal, but numbers are
+// decoded as json.Number, so large integers, like epoch times in
+// nanoseconds, keep their precision.
+func unmarshalJSON(data []byte, value any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(value); err != nil {
+		return err
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return errTrailingData
+	}
+
+	return nil
+}
+
+// isObjectStart returns true if the brace is followed by a key or by the
+// closing brace, so it can start a JSON object.
+func isObjectStart(value []byte) bool {
+	rest := bytes.TrimLeft(value[1:], " \t\r\n")
+
+	return len(rest) > 0 && (rest[0] == '"' || rest[0] == '}')
+}
+
 func getPlainLogEntry(
 	line json.RawMessage,
 	cfg *config.Config,
@@ -364,6 +749,7 @@ func guessTimeFieldKind(timeStr string) config.FieldKind {
 		unixSecondsLength = 10
 		unixMilliLength   = 13
 		unixMicroLength   = 16
+		unixNanoLength    = 19
 	)
 
 	switch {
@@ -373,16 +759,24 @@ func guessTimeFieldKind(timeStr string) config.FieldKind {
 		return config.FieldKindMilliTime
 	case intLength > unixMilliLength && intLength <= unixMicroLength:
 		return config.FieldKindMicroTime
+	case intLength > unixMicroLength && intLength <= unixNanoLength:
+		return config.FieldKindNanoTime
 	default:
 		return config.FieldKindTime
 	}
 }
 
-func formatTimeValue(timeValue string, unit string, format string) string {
+// formatTimeValue formats the epoch time in the location, or in UTC if the
+// location is nil.
+func formatTimeValue(timeValue string, unit string, format string, location *time.Location) string {
 	duration, err := time.ParseDuration(timeValue + unit)
 	if err != nil {
 		return timeValue
 	}
 
-	return time.UnixMilli(0).Add(duration).UTC().Format(format)
+	if location == nil {
+		location = time.UTC
+	}
+
+	return time.UnixMilli(0).Add(duration).In(location).Format(format)
 }
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"math"
 	"os"
-	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -204,6 +203,32 @@ func TestParseLogEntryDefault(t *testing.T) {
 				fieldKindToValue,
 			)
 		},
+	}, {
+		Name: "prefixed_json",
+		JSON: `2026-10-17T10:00:00Z stdout F {"level":"info","msg":"hello"}`,
+		Assert: func(tb testing.TB, fieldKindToValue map[config.FieldKind]string) {
+			tb.Helper()
+
+			assert.Equal(t, "info", fieldKindToValue[config.FieldKindLevel], fieldKindToValue)
+			assert.Equal(t, "hello", fieldKindToValue[config.FieldKindMessage], fieldKindToValue)
+		},
+	}, {
+		Name: "prefixed_json_brace_in_prefix",
+		JSON: `[{main}] {"level":"warn","msg":"hello"} trailing`,
+		Assert: func(tb testing.TB, fieldKindToValue map[config.FieldKind]string) {
+			tb.Helper()
+
+			assert.Equal(t, "warn", fieldKindToValue[config.FieldKindLevel], fieldKindToValue)
+			assert.Equal(t, "hello", fieldKindToValue[config.FieldKindMessage], fieldKindToValue)
+		},
+	}, {
+		Name: "json_array",
+		JSON: `[{"level":"info","msg":"hello"}]`,
+		Assert: func(tb testing.TB, fieldKindToValue map[config.FieldKind]string) {
+			tb.Helper()
+
+			assert.Equal(t, "-", fieldKindToValue[config.FieldKindLevel], fieldKindToValue)
+		},
 	}}
 
 	for _, testCase := range testCases {
@@ -218,6 +243,265 @@ func TestParseLogEntryDefault(t *testing.T) {
 	}
 }
 
+func TestParseLogEntryPrefix(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	cfg.Fields = append(cfg.Fields, config.Field{
+		Title:      "Prefix",
+		Kind:       config.FieldKindAny,
+		References: []string{"$." + source.PrefixFieldName},
+	})
+
+	t.Run("prefixed", func(t *testing.T) {
+		t.Parallel()
+
+		const line = `2026-10-17T10:00:00Z stdout F {"msg":"hello"}`
+
+		entries := parseLogEntries(t, line, cfg)
+		entry := entries.LogEntry(cfg, 0)
+
+		assert.Equal(t, "2026-10-17T10:00:00Z stdout F", entry.Fields[len(entry.Fields)-1])
+		assert.JSONEq(t, `{"msg":"hello"}`, string(entry.Content()))
+	})
+
+	t.Run("not_prefixed", func(t *testing.T) {
+		t.Parallel()
+
+		const line = `{"msg":"hello"}`
+
+		entries := parseLogEntries(t, line, cfg)
+		entry := entries.LogEntry(cfg, 0)
+
+		assert.Equal(t, "-", entry.Fields[len(entry.Fields)-1])
+		assert.JSONEq(t, line, string(entry.Content()))
+	})
+
+	t.Run("field_exists", func(t *testing.T) {
+		t.Parallel()
+
+		const line = `stdout {"_prefix":"original"}`
+
+		entries := parseLogEntries(t, line, cfg)
+		entry := entries.LogEntry(cfg, 0)
+
+		assert.Equal(t, "original", entry.Fields[len(entry.Fields)-1])
+	})
+
+	t.Run("plain", func(t *testing.T) {
+		t.Parallel()
+
+		const line = `stdout {not json}`
+
+		entries := parseLogEntries(t, line, cfg)
+		entry := entries.LogEntry(cfg, 0)
+
+		assert.Empty(t, entry.JSON)
+		assert.Equal(t, line+"\n", string(entry.Content()))
+	})
+
+	t.Run("braces_before_object", func(t *testing.T) {
+		t.Parallel()
+
+		line := strings.Repeat("func() { return } ", 100) + `{"msg":"hello"}`
+
+		entries := parseLogEntries(t, line, cfg)
+		entry := entries.LogEntry(cfg, 0)
+
+		assert.JSONEq(t, `{"msg":"hello"}`, string(entry.Content()))
+	})
+
+	t.Run("many_invalid_objects", func(t *testing.T) {
+		t.Parallel()
+
+		line := strings.Repeat(`{"key" `, 100) + `{"msg":"hello"}`
+
+		entries := parseLogEntries(t, line, cfg)
+		entry := entries.LogEntry(cfg, 0)
+
+		assert.JSONEq(t, `{"msg":"hello"}`, string(entry.Content()))
+	})
+
+	t.Run("braces_in_strings", func(t *testing.T) {
+		t.Parallel()
+
+		line := `{not json} {"key" {"msg":"hello } {"}}`
+
+		entries := parseLogEntries(t, line, cfg)
+		entry := entries.LogEntry(cfg, 0)
+
+		assert.JSONEq(t, `{"msg":"hello } {"}`, string(entry.Content()))
+	})
+}
+
+func TestParseLogEntryUnwrap(t *testing.T) {
+	t.Parallel()
+
+	const dockerLine = `{"log":"{\"level\":\"error\",\"msg\":\"failed\"}\n","stream":"stdout","time":"2026-10-17T10:00:00Z"}`
+
+	testCases := [...]struct {
+		Name            string
+		JSON            string
+		Unwrap          []config.Unwrap
+		ExpectedFields  []string
+		ExpectedContent string
+	}{{
+		Name:            "disabled",
+		JSON:            dockerLine,
+		Unwrap:          nil,
+		ExpectedFields:  []string{"2026-10-17T10:00:00Z", "-", "-"},
+		ExpectedContent: dockerLine,
+	}, {
+		Name:            "merge",
+		JSON:            dockerLine,
+		Unwrap:          []config.Unwrap{{Field: "log", Merge: true}},
+		ExpectedFields:  []string{"2026-10-17T10:00:00Z", "error", "failed"},
+		ExpectedContent: `{"level":"error","msg":"failed","stream":"stdout","time":"2026-10-17T10:00:00Z"}`,
+	}, {
+		Name:            "nested",
+		JSON:            `{"msg":"{\"text\":\"hello\"}","level":"info"}`,
+		Unwrap:          []config.Unwrap{{Field: "msg"}},
+		ExpectedFields:  []string{"-", "info", `{"text":"hello"}`},
+		ExpectedContent: `{"msg":{"text":"hello"},"level":"info"}`,
+	}, {
+		Name:            "array",
+		JSON:            `{"msg":"[1,2]"}`,
+		Unwrap:          []config.Unwrap{{Field: "msg", Merge: true}},
+		ExpectedFields:  []string{"-", "-", "[1,2]"},
+		ExpectedContent: `{"msg":[1,2]}`,
+	}, {
+		Name:            "not_json",
+		JSON:            `{"msg":"hello"}`,
+		Unwrap:          []config.Unwrap{{Field: "msg", Merge: true}},
+		ExpectedFields:  []string{"-", "-", "hello"},
+		ExpectedContent: `{"msg":"hello"}`,
+	}, {
+		Name:            "scalar",
+		JSON:            `{"msg":"123"}`,
+		Unwrap:          []config.Unwrap{{Field: "msg"}},
+		ExpectedFields:  []string{"-", "-", "123"},
+		ExpectedContent: `{"msg":"123"}`,
+	}, {
+		Name:            "not_string",
+		JSON:            `{"msg":{"text":"hello"}}`,
+		Unwrap:          []config.Unwrap{{Field: "msg", Merge: true}},
+		ExpectedFields:  []string{"-", "-", `{"text":"hello"}`},
+		ExpectedContent: `{"msg":{"text":"hello"}}`,
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.GetDefaultConfig()
+			cfg.Unwrap = testCase.Unwrap
+
+			entry := parseLogEntries(t, testCase.JSON, cfg).LogEntry(cfg, 0)
+
+			assert.Equal(t, testCase.ExpectedFields, entry.Fields)
+			assert.JSONEq(t, testCase.ExpectedContent, string(entry.Content()))
+		})
+	}
+}
+
+func TestParseLogEntryProfile(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	cfg.Fields = []config.Field{{
+		Title:      "Level",
+		Kind:       config.FieldKindLevel,
+		References: []string{"$.level"},
+	}, {
+		Title:      "Message",
+		Kind:       config.FieldKindMessage,
+		References: []string{"$.message"},
+	}, {
+		Title:      "Host",
+		Kind:       config.FieldKindAny,
+		References: []string{"$.host"},
+	}}
+	cfg.Profiles = []config.Profile{{
+		Name:  "gcp",
+		Match: config.ProfileMatch{Keys: []string{"$.severity", "$.textPayload"}},
+		Fields: []config.Field{{
+			Title:      "Message",
+			Kind:       config.FieldKindMessage,
+			References: []string{"$.textPayload"},
+		}, {
+			Title:      "level",
+			Kind:       config.FieldKindLevel,
+			References: []string{"$.severity"},
+		}},
+		CustomLevelMapping: map[string]string{"notice": "info"},
+	}}
+
+	input := `{"level":"error","message":"plain","host":"a"}
+{"severity":"NOTICE","textPayload":"cloud"}
+{"severity":"ERROR","message":"partial"}
+`
+
+	entries := requireParseLogEntries(t, input, cfg)
+	require.Equal(t, 3, entries.Len())
+
+	assert.Equal(t, []string{"error", "plain", "a"}, entries.LogEntry(cfg, 0).Fields)
+	assert.Equal(t, []string{"info", "cloud", "-"}, entries.LogEntry(cfg, 1).Fields)
+	assert.Equal(t, []string{"-", "partial", "-"}, entries.LogEntry(cfg, 2).Fields)
+
+	// Columns of the profile follow changes of the columns of the config.
+	changed := *cfg
+	changed.Fields = cfg.Fields[1:]
+	assert.Equal(t, []string{"cloud", "-"}, entries.LogEntry(&changed, 1).Fields)
+
+	// Entries are not matched if the profile is selected for the log.
+	selected := cfg.WithProfile(config.Profile{Name: "default", Fields: cfg.Fields})
+	assert.Equal(t, []string{"-", "-", "-"}, entries.LogEntry(selected, 1).Fields)
+}
+
+func TestDetectProfile(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	cfg.Profiles = []config.Profile{{
+		Name:   "gcp",
+		Match:  config.ProfileMatch{Keys: []string{"$.severity"}},
+		Fields: cfg.Fields,
+	}}
+
+	testCases := [...]struct {
+		Name     string
+		Input    string
+		Expected string
+	}{{
+		Name:     "first_json",
+		Input:    "starting\n\n{\"severity\":\"INFO\"}\n",
+		Expected: "gcp",
+	}, {
+		Name:     "without_newline",
+		Input:    `{"severity":"INFO"}`,
+		Expected: "gcp",
+	}, {
+		Name:     "other_keys",
+		Input:    `{"level":"INFO"}` + "\n" + `{"severity":"INFO"}`,
+		Expected: "",
+	}, {
+		Name:     "empty",
+		Input:    "",
+		Expected: "",
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			profile, ok := source.DetectProfile(strings.NewReader(testCase.Input), cfg)
+
+			assert.Equal(t, testCase.Expected != "", ok)
+			assert.Equal(t, testCase.Expected, profile.Name)
+		})
+	}
+}
+
 func TestLogEntryRow(t *testing.T) {
 	t.Parallel()
 
@@ -480,6 +764,64 @@ func TestLazyLogEntriesFieldFilter(t *testing.T) {
 	})
 }
 
+func TestLazyLogEntriesFilterByLevel(t *testing.T) {
+	t.Parallel()
+
+	const logs = `{"level":"info","message":"first"}
+{"level":"warn","message":"second"}
+{"level":"fatal","message":"third"}
+plain text
+`
+
+	createEntries := func(tb testing.TB) source.LazyLogEntries {
+		tb.Helper()
+
+		return requireParseLogEntries(tb, logs, config.GetDefaultConfig())
+	}
+
+	t.Run("warn", func(t *testing.T) {
+		t.Parallel()
+
+		filtered, err := createEntries(t).FilterByLevel(source.LevelWarning, config.GetDefaultConfig())
+		require.NoError(t, err)
+
+		if assert.Len(t, filtered.Entries, 2) {
+			assert.Equal(t, 1, filtered.Entries[0].Index())
+			assert.Equal(t, 2, filtered.Entries[1].Index())
+		}
+	})
+
+	t.Run("no_level_column", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.GetDefaultConfig()
+		cfg.Fields = cfg.Fields[2:]
+
+		_, err := createEntries(t).FilterByLevel(source.LevelWarning, cfg)
+		require.ErrorIs(t, err, source.ErrInvalidFilter)
+	})
+
+	t.Run("nil_config", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := createEntries(t).FilterByLevel(source.LevelWarning, nil)
+		require.ErrorIs(t, err, source.ErrInvalidFilter)
+	})
+}
+
+func TestLazyLogEntriesFilterByIndex(t *testing.T) {
+	t.Parallel()
+
+	logEntries := requireParseLogEntries(t, "first\nsecond\nthird\n", config.GetDefaultConfig())
+
+	filtered := logEntries.FilterByIndex(func(index int) bool { return index != 1 })
+
+	if assert.Len(t, filtered.Entries, 2) {
+		assert.Equal(t, 0, filtered.Entries[0].Index())
+		assert.Equal(t, 2, filtered.Entries[1].Index())
+	}
+}
+
 // TestNewMatcher pins how a term is classified as a regular expression,
 // which the filter tests above cannot show on their own: their fixtures hold
 // no slash, so a substring term and a broken matcher both return no entries.
@@ -704,9 +1046,22 @@ func TestNumericKindTimeFormatting(t *testing.T) {
 		JSON:           `{"timestamp":12345678900000.222}`,
 		ExpectedOutput: time.Unix(12345678, 0).UTC().Format(time.RFC3339),
 	}, {
+		TestName:       "17 character int is in nanoseconds",
+		JSON:           `{"timestamp":12345678000000000}`,
+		ExpectedOutput: time.Unix(12345678, 0).UTC().Format(time.RFC3339),
+	}, {
+		TestName:       "19 character string is in nanoseconds",
+		JSON:           `{"timestamp":"1767225600123456789"}`,
+		ExpectedOutput: time.Unix(1767225600, 0).UTC().Format(time.RFC3339),
+	}, {
+		// The longest nanosecond epoch.
 		TestName:       "max_int64",
 		JSON:           fmt.Sprintf(`{"timestamp":"%d"}`, math.MaxInt64),
-		ExpectedOutput: strconv.Itoa(math.MaxInt64),
+		ExpectedOutput: time.Unix(0, math.MaxInt64).UTC().Format(time.RFC3339),
+	}, {
+		TestName:       "too_long",
+		JSON:           `{"timestamp":"12345678901234567890"}`,
+		ExpectedOutput: "12345678901234567890",
 	}, {
 		TestName:       "negative",
 		JSON:           `{"timestamp":"-1"}`,
@@ -860,6 +1215,73 @@ func TestTimeFormat(t *testing.T) {
 	}
 }
 
+func TestTimeZone(t *testing.T) {
+	t.Parallel()
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	logDate := time.Date(2000, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := [...]struct {
+		Name          string
+		Kind          config.FieldKind
+		JSON          string
+		TimeZone      string
+		FieldTimeZone string
+		Expected      string
+	}{{
+		Name:     "epoch_utc_by_default",
+		Kind:     config.FieldKindSecondTime,
+		JSON:     fmt.Sprintf(`{"timestamp":%d}`, logDate.Unix()),
+		Expected: logDate.Format(time.RFC3339),
+	}, {
+		Name:     "epoch",
+		Kind:     config.FieldKindNumericTime,
+		JSON:     fmt.Sprintf(`{"timestamp":%d}`, logDate.UnixNano()),
+		TimeZone: "Asia/Tokyo",
+		Expected: logDate.In(tokyo).Format(time.RFC3339),
+	}, {
+		Name:     "parsed_keeps_zone_by_default",
+		Kind:     config.FieldKindTime,
+		JSON:     `{"timestamp":"2000-01-02T05:04:05+02:00"}`,
+		Expected: "2000-01-02T05:04:05+02:00",
+	}, {
+		Name:     "parsed",
+		Kind:     config.FieldKindTime,
+		JSON:     `{"timestamp":"2000-01-02T05:04:05+02:00"}`,
+		TimeZone: "UTC",
+		Expected: logDate.Format(time.RFC3339),
+	}, {
+		Name:          "field_overrides_config",
+		Kind:          config.FieldKindSecondTime,
+		JSON:          fmt.Sprintf(`{"timestamp":%d}`, logDate.Unix()),
+		TimeZone:      "Asia/Tokyo",
+		FieldTimeZone: "utc",
+		Expected:      logDate.Format(time.RFC3339),
+	}, {
+		Name:     "local",
+		Kind:     config.FieldKindSecondTime,
+		JSON:     fmt.Sprintf(`{"timestamp":%d}`, logDate.Unix()),
+		TimeZone: "Local",
+		Expected: logDate.In(time.Local).Format(time.RFC3339),
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := getTimestampFormattingConfig(testCase.Kind, time.RFC3339)
+			cfg.TimeLayouts = []string{time.RFC3339}
+			cfg.TimeZone = testCase.TimeZone
+			cfg.Fields[0].TimeZone = testCase.FieldTimeZone
+
+			actual := parseTableRow(t, testCase.JSON, cfg)
+			assert.Equal(t, testCase.Expected, actual[0])
+		})
+	}
+}
+
 func parseLazyLogEntry(tb testing.TB, value string, cfg *config.Config) source.LazyLogEntry {
 	tb.Helper()
 
@@ -878,6 +1300,12 @@ func parseLazyLogEntry(tb testing.TB, value string, cfg *config.Config) source.L
 func parseTableRow(tb testing.TB, value string, cfg *config.Config) table.Row {
 	tb.Helper()
 
+	return parseLogEntries(tb, value, cfg).Row(cfg, 0)
+}
+
+func parseLogEntries(tb testing.TB, value string, cfg *config.Config) source.LazyLogEntries {
+	tb.Helper()
+
 	source, err := source.Reader(strings.NewReader(value+"\n"), cfg)
 	require.NoError(tb, err)
 
@@ -887,7 +1315,7 @@ func parseTableRow(tb testing.TB, value string, cfg *config.Config) table.Row {
 	require.NoError(tb, err)
 	require.Equal(tb, 1, logEntries.Len(), value)
 
-	return logEntries.Row(cfg, 0)
+	return logEntries
 }
 
 func getFieldKindToValue(cfg *config.Config, entries []string) map[config.FieldKind]string {
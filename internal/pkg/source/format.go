@@ -0,0 +1,302 @@
+package source
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+)
+
+// jsonArrayInput is a state of reading entries from top-level JSON arrays.
+type jsonArrayInput struct {
+	// inArray is true if the opening bracket has been read.
+	inArray bool
+	// elements is the number of elements read from the current array.
+	elements int
+}
+
+// readJSONArrayElement reads the next element of a top-level JSON array.
+// Several arrays can follow each other, for example, if multiple files are
+// opened.
+func (s *Source) readJSONArrayElement() (LazyLogEntry, error) {
+	for {
+		c, err := s.readNonSpaceByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) && s.jsonArray.inArray {
+				return LazyLogEntry{}, fmt.Errorf("%w: unexpected end of JSON array", ErrInvalidInput)
+			}
+
+			return LazyLogEntry{}, err
+		}
+
+		switch {
+		case !s.jsonArray.inArray && c == '[':
+			s.jsonArray = jsonArrayInput{inArray: true}
+
+			continue
+		case !s.jsonArray.inArray:
+			return LazyLogEntry{}, fmt.Errorf("%w: expected JSON array at offset %d", ErrInvalidInput, s.offset-1)
+		case c == ']':
+			s.jsonArray.inArray = false
+
+			continue
+		case s.jsonArray.elements > 0 && c != ',':
+			return LazyLogEntry{}, fmt.Errorf("%w: expected comma at offset %d", ErrInvalidInput, s.offset-1)
+		case s.jsonArray.elements > 0:
+			c, err = s.readNonSpaceByte()
+			if err != nil {
+				return LazyLogEntry{}, fmt.Errorf("%w: reading JSON array element: %w", ErrInvalidInput, unexpectedEOF(err))
+			}
+		}
+
+		offset := s.offset - 1
+
+		if err := s.skipJSONValue(c); err != nil {
+			return LazyLogEntry{}, fmt.Errorf("%w: reading JSON array element: %w", ErrInvalidInput, err)
+		}
+
+		s.jsonArray.elements++
+
+		return LazyLogEntry{
+			offset: offset,
+			length: int(s.offset - offset),
+		}, nil
+	}
+}
+
+// skipJSONValue reads the rest of the JSON value that starts with the byte
+// first.
+func (s *Source) skipJSONValue(first byte) error {
+	// Strings are skipped separately, because the balance expects the whole
+	// line to be fed at once to track them.
+	if first == '"' {
+		return s.skipJSONString()
+	}
+
+	balance := jsonBalance{}.feed([]byte{first})
+
+	if !balance.isOpen() {
+		// It is a number, a boolean or null. It ends before a delimiter.
+		for {
+			next, err := s.reader.Peek(1)
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					return io.ErrUnexpectedEOF
+				}
+
+				return err
+			}
+
+			if next[0] == ',' || next[0] == ']' || isJSONSpace(next[0]) {
+				return nil
+			}
+
+			if _, err := s.readByte(); err != nil {
+				return err
+			}
+		}
+	}
+
+	for balance.isOpen() {
+		c, err := s.readByte()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+
+		if c == '"' {
+			if err := s.skipJSONString(); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		balance = balance.feed([]byte{c})
+	}
+
+	return nil
+}
+
+// skipJSONString reads the rest of the string literal.
+func (s *Source) skipJSONString() error {
+	escaped := false
+
+	for {
+		c, err := s.readByte()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			return nil
+		}
+	}
+}
+
+func (s *Source) readNonSpaceByte() (byte, error) {
+	for {
+		c, err := s.readByte()
+		if err != nil {
+			return 0, err
+		}
+
+		if !isJSONSpace(c) {
+			return c, nil
+		}
+	}
+}
+
+func (s *Source) readByte() (byte, error) {
+	c, err := s.reader.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	s.offset++
+
+	return c, nil
+}
+
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// csvInput is a state of reading entries from values separated by commas or
+// tabs.
+type csvInput struct {
+	reader  *csv.Reader
+	decoder *csvDecoder
+}
+
+// readCSVRecord reads the next record. The first record is a header with the
+// names of the fields. Records equal to the header are skipped, so several
+// files with the same header can be opened at once.
+func (s *Source) readCSVRecord() (LazyLogEntry, error) {
+	if s.csv.reader == nil {
+		s.csv.reader = newCSVReader(s.reader, s.csv.decoder.comma)
+	}
+
+	for {
+		offset := s.csv.reader.InputOffset()
+
+		record, err := s.csv.reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return LazyLogEntry{}, io.EOF
+			}
+
+			return LazyLogEntry{}, fmt.Errorf("%w: reading csv: %w", ErrInvalidInput, err)
+		}
+
+		s.offset = s.csv.reader.InputOffset()
+
+		if s.csv.decoder.header == nil {
+			s.csv.decoder.header = slices.Clone(record)
+
+			continue
+		}
+
+		if slices.Equal(record, s.csv.decoder.header) {
+			continue
+		}
+
+		return LazyLogEntry{
+			offset:  offset,
+			length:  int(s.offset - offset),
+			decoder: s.csv.decoder,
+		}, nil
+	}
+}
+
+func newCSVReader(reader io.Reader, comma rune) *csv.Reader {
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma = comma
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+
+	return csvReader
+}
+
+// csvDecoder converts a record to a JSON object using the names of the fields
+// from the header.
+type csvDecoder struct {
+	comma  rune
+	header []string
+}
+
+// decode returns the JSON object of the record. It keeps the order of the
+// fields. Fields that are missing in the header are named by their position,
+// starting from 1.
+func (d *csvDecoder) decode(line []byte) (json.RawMessage, error) {
+	record, err := newCSVReader(bytes.NewReader(line), d.comma).Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading csv: %w", err)
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, value := range record {
+		name := "column" + strconv.Itoa(i+1)
+		if i < len(d.header) {
+			name = d.header[i]
+		}
+
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		encodedName, err := json.Marshal(name)
+		if err != nil {
+			return nil, fmt.Errorf("encoding name: %w", err)
+		}
+
+		encodedValue, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("encoding value: %w", err)
+		}
+
+		buf.Write(encodedName)
+		buf.WriteByte(':')
+		buf.Write(encodedValue)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// newInputDecoder returns a decoder of records for the input format. It
+// returns nil if records are read as they are.
+func newInputDecoder(format config.InputFormat) (*csvDecoder, error) {
+	switch format {
+	case "", config.InputFormatLines, config.InputFormatJSON:
+		return nil, nil //nolint:nilnil // Records are not decoded.
+	case config.InputFormatCSV:
+		return &csvDecoder{comma: ','}, nil
+	case config.InputFormatTSV:
+		return &csvDecoder{comma: '\t'}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown input format: %s", ErrInvalidInput, format)
+	}
+}
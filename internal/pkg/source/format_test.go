@@ -0,0 +1,239 @@
+package source_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/tests"
+)
+
+func TestParseLogEntriesJSONArray(t *testing.T) {
+	t.Parallel()
+
+	testCases := [...]struct {
+		Name     string
+		Input    string
+		Expected []string
+	}{{
+		Name:     "empty",
+		Input:    " [ ] ",
+		Expected: []string{},
+	}, {
+		Name:  "compact",
+		Input: `[{"msg":"first"},{"msg":"second ]"}]`,
+		Expected: []string{
+			`{"msg":"first"}`,
+			`{"msg":"second ]"}`,
+		},
+	}, {
+		Name: "pretty",
+		Input: `[
+  {
+    "msg": "first \"}\"",
+    "tags": ["a", "b"]
+  },
+  {"msg": "second"}
+]
+`,
+		Expected: []string{
+			"{\n    \"msg\": \"first \\\"}\\\"\",\n    \"tags\": [\"a\", \"b\"]\n  }",
+			`{"msg": "second"}`,
+		},
+	}, {
+		Name:     "scalars",
+		Input:    `["text", 1.5, true, null]`,
+		Expected: []string{`"text"`, `1.5`, `true`, `null`},
+	}, {
+		Name:     "several_arrays",
+		Input:    "[{\"msg\":\"first\"}]\n[{\"msg\":\"second\"}]\n",
+		Expected: []string{`{"msg":"first"}`, `{"msg":"second"}`},
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.GetDefaultConfig()
+			cfg.InputFormat = config.InputFormatJSON
+
+			logEntries := requireParseLogEntries(t, testCase.Input, cfg)
+
+			actual := make([]string, 0, logEntries.Len())
+
+			for i := range logEntries.Len() {
+				entry := logEntries.LogEntry(cfg, i)
+				require.NoError(t, entry.Error)
+
+				assert.Equal(t, i, entry.Index)
+
+				actual = append(actual, string(entry.Line))
+			}
+
+			assert.Equal(t, testCase.Expected, actual)
+		})
+	}
+
+	t.Run("rendered", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.GetDefaultConfig()
+		cfg.InputFormat = config.InputFormatJSON
+
+		logEntries := requireParseLogEntries(t, `[{"level":"error","msg":"failed"}]`, cfg)
+		require.Equal(t, 1, logEntries.Len())
+
+		assert.Equal(t, []string{"-", "error", "failed"}, []string(logEntries.Row(cfg, 0)))
+	})
+
+	invalidInputs := [...]string{
+		`{"msg":"not array"}`,
+		`[{"msg":"first"} {"msg":"second"}]`,
+		`[{"msg":"unclosed"`,
+		`[{"msg":"first"},`,
+		`["unclosed`,
+		`[1`,
+	}
+
+	for _, input := range invalidInputs {
+		t.Run("invalid_"+input, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.GetDefaultConfig()
+			cfg.InputFormat = config.InputFormatJSON
+
+			inputSource, err := source.Reader(strings.NewReader(input), cfg)
+			require.NoError(t, err)
+
+			t.Cleanup(func() { assert.NoError(t, inputSource.Close()) })
+
+			_, err = inputSource.ParseLogEntries()
+			require.ErrorIs(t, err, source.ErrInvalidInput)
+		})
+	}
+}
+
+func TestParseLogEntriesCSV(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	cfg.InputFormat = config.InputFormatCSV
+
+	const input = `time,level,msg
+2026-10-17T10:00:00Z,info,started
+2026-10-17T10:00:01Z,error,"failed, ""quoted""
+on two lines"
+time,level,msg
+2026-10-17T10:00:02Z,warn,extra,value
+`
+
+	logEntries := requireParseLogEntries(t, input, cfg)
+	require.Equal(t, 3, logEntries.Len())
+
+	t.Run("rows", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t,
+			[]string{"2026-10-17T10:00:00Z", "info", "started"},
+			[]string(logEntries.Row(cfg, 0)),
+		)
+		assert.Equal(t,
+			[]string{"2026-10-17T10:00:01Z", "error", `failed, "quoted"\non two lines`},
+			[]string(logEntries.Row(cfg, 1)),
+		)
+	})
+
+	t.Run("content", func(t *testing.T) {
+		t.Parallel()
+
+		entry := logEntries.LogEntry(cfg, 2)
+		require.NoError(t, entry.Error)
+
+		assert.Equal(t, 2, entry.Index)
+		assert.Equal(t, "2026-10-17T10:00:02Z,warn,extra,value\n", string(entry.Line))
+		assert.JSONEq(t,
+			`{"time":"2026-10-17T10:00:02Z","level":"warn","msg":"extra","column4":"value"}`,
+			string(entry.Content()),
+		)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		t.Parallel()
+
+		filtered, err := logEntries.Filter("error", "level", cfg)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, filtered.Len())
+	})
+}
+
+func TestParseLogEntriesTSV(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	cfg.InputFormat = config.InputFormatTSV
+
+	logEntries := requireParseLogEntries(t, "level\tmsg\ninfo\thello, world\n", cfg)
+	require.Equal(t, 1, logEntries.Len())
+
+	assert.Equal(t, []string{"-", "info", "hello, world"}, []string(logEntries.Row(cfg, 0)))
+}
+
+func TestInputFormatInvalid(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	cfg.InputFormat = "xml"
+
+	_, err := source.Reader(strings.NewReader(""), cfg)
+	require.ErrorIs(t, err, source.ErrInvalidInput)
+
+	_, err = source.File(tests.RequireCreateFile(t, nil), cfg)
+	require.ErrorIs(t, err, source.ErrInvalidInput)
+}
+
+func TestInputFormatCanFollow(t *testing.T) {
+	t.Parallel()
+
+	fileName := tests.RequireCreateFile(t, nil)
+
+	for _, format := range []config.InputFormat{"", config.InputFormatLines} {
+		cfg := config.GetDefaultConfig()
+		cfg.InputFormat = format
+
+		inputSource, err := source.File(fileName, cfg)
+		require.NoError(t, err)
+
+		assert.True(t, inputSource.CanFollow(), format)
+		assert.NoError(t, inputSource.Close())
+	}
+
+	for _, format := range []config.InputFormat{config.InputFormatJSON, config.InputFormatCSV, config.InputFormatTSV} {
+		cfg := config.GetDefaultConfig()
+		cfg.InputFormat = format
+
+		inputSource, err := source.File(fileName, cfg)
+		require.NoError(t, err)
+
+		assert.False(t, inputSource.CanFollow(), format)
+		assert.NoError(t, inputSource.Close())
+	}
+}
+
+func requireParseLogEntries(tb testing.TB, input string, cfg *config.Config) source.LazyLogEntries {
+	tb.Helper()
+
+	inputSource, err := source.Reader(strings.NewReader(input), cfg)
+	require.NoError(tb, err)
+
+	tb.Cleanup(func() { assert.NoError(tb, inputSource.Close()) })
+
+	logEntries, err := inputSource.ParseLogEntries()
+	require.NoError(tb, err)
+
+	return logEntries
+}
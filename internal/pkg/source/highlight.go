@@ -0,0 +1,186 @@
+package source
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/yalp/jsonpath"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+)
+
+// Highlighter matches entries with highlight rules of the config.
+type Highlighter struct {
+	rules      []highlightRule
+	levelIndex int
+	cfg        *config.Config
+}
+
+type highlightRule struct {
+	config.Highlight
+
+	matchTerm func(value []byte) bool
+	getText   func(entry *sortEntry) ([]byte, bool)
+	getValue  func(entry *sortEntry) sortValue
+	// value is the value of the rule that is compared by Op.
+	value sortValue
+}
+
+// NewHighlighter prepares highlight rules for columns of the config. It
+// fails if a term holds an invalid regular expression or a field holds an
+// invalid JSONPath. Rules with unknown titles of columns don't match.
+func NewHighlighter(rules []config.Highlight, cfg *config.Config) (Highlighter, error) {
+	prepared := make([]highlightRule, 0, len(rules))
+
+	for i, rule := range rules {
+		if strings.HasPrefix(rule.Field, "$") {
+			if _, err := jsonpath.Prepare(rule.Field); err != nil {
+				return Highlighter{}, fmt.Errorf("%w: %d: %s: %w", ErrInvalidHighlight, i, rule.Field, err)
+			}
+		}
+
+		prepared = append(prepared, highlightRule{Highlight: rule})
+		current := &prepared[len(prepared)-1]
+
+		if rule.Term != "" {
+			var err error
+
+			current.matchTerm, err = NewMatcher(rule.Term)
+			if err != nil {
+				return Highlighter{}, fmt.Errorf("%w: %d: %w", ErrInvalidHighlight, i, err)
+			}
+
+			current.getText = highlightTextGetter(rule.Field, cfg)
+		}
+
+		if rule.Op != "" {
+			getValue, err := sortValueGetter(rule.Field, cfg)
+			if err != nil {
+				getValue = func(*sortEntry) sortValue { return sortValue{missing: true} }
+			}
+
+			current.getValue = getValue
+			// The value of the rule is parsed by the kind of the column, so
+			// "1s" can be compared with durations.
+			current.value = textSortValue(rule.Value, sortField(rule.Field, cfg), sortTimeLayouts(cfg))
+		}
+	}
+
+	return Highlighter{
+		rules:      prepared,
+		levelIndex: getFieldKindIndex(config.FieldKindLevel, cfg),
+		cfg:        cfg,
+	}, nil
+}
+
+// Match returns rules that match the entry in the order of the config.
+func (h Highlighter) Match(entry LogEntry) []config.Highlight {
+	if entry.Error != nil {
+		return nil
+	}
+
+	var matched []config.Highlight
+
+	// The JSON of the entry is parsed once for all rules.
+	parsed := &sortEntry{LogEntry: entry}
+
+	for _, rule := range h.rules {
+		if h.matches(rule, parsed) {
+			matched = append(matched, rule.Highlight)
+		}
+	}
+
+	return matched
+}
+
+func (h Highlighter) matches(r highlightRule, entry *sortEntry) bool {
+	if r.Level != "" {
+		if h.levelIndex < 0 || h.levelIndex >= len(entry.Fields) ||
+			!Level(entry.Fields[h.levelIndex]).AtLeast(Level(strings.ToLower(r.Level)), h.cfg) {
+			return false
+		}
+	}
+
+	if r.matchTerm != nil {
+		value, ok := r.getText(entry)
+		if !ok || !r.matchTerm(value) {
+			return false
+		}
+	}
+
+	if r.getValue != nil {
+		// The value of the field is compared like entries are compared by
+		// the sort.
+		value := r.getValue(entry)
+		if value.missing {
+			return false
+		}
+
+		return compareResult(r.Op, value.compare(r.value))
+	}
+
+	return true
+}
+
+// highlightTextGetter returns a function that gets the text of the field, or
+// the whole line if the field is empty.
+func highlightTextGetter(fieldName string, cfg *config.Config) func(entry *sortEntry) ([]byte, bool) {
+	if fieldName == "" {
+		return func(entry *sortEntry) ([]byte, bool) {
+			return bytes.TrimRight(entry.Line, "\r\n"), true
+		}
+	}
+
+	if !strings.HasPrefix(fieldName, "$") {
+		fieldIndex := getFilterFieldNameIndex(fieldName, cfg)
+
+		return func(entry *sortEntry) ([]byte, bool) {
+			if fieldIndex < 0 || fieldIndex >= len(entry.Fields) {
+				return nil, false
+			}
+
+			return bytes.TrimRight([]byte(entry.Fields[fieldIndex]), "\r\n"), true
+		}
+	}
+
+	read, err := jsonpath.Prepare(fieldName)
+	if err != nil {
+		return func(*sortEntry) ([]byte, bool) { return nil, false }
+	}
+
+	return func(entry *sortEntry) ([]byte, bool) {
+		parsedLine, ok := entry.jsonObject()
+		if !ok {
+			return nil, false
+		}
+
+		found, err := read(parsedLine)
+		if err != nil || found == nil {
+			return nil, false
+		}
+
+		if text, ok := found.(string); ok {
+			return []byte(text), true
+		}
+
+		text, err := json.Marshal(found)
+		if err != nil {
+			return nil, false
+		}
+
+		return text, true
+	}
+}
+
+// getFieldKindIndex returns the index of the first column of the kind.
+func getFieldKindIndex(kind config.FieldKind, cfg *config.Config) int {
+	for i, field := range cfg.Fields {
+		if field.Kind == kind {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,130 @@
+package source_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
+)
+
+func TestHighlighterMatch(t *testing.T) {
+	t.Parallel()
+
+	const input = `{"time":"2026-01-01T00:00:00Z","level":"info","message":"request","duration_ms":1500,"tenant":"acme"}
+{"time":"2026-01-01T00:00:01Z","level":"error","message":"failed","duration_ms":20,"tenant":"other"}
+{"time":"2026-01-01T00:00:02Z","level":"debug","message":"cache miss"}
+`
+
+	testCases := [...]struct {
+		Name      string
+		Highlight config.Highlight
+		Expected  []int
+	}{{
+		Name:      "term_line",
+		Highlight: config.Highlight{Term: "ACME"},
+		Expected:  []int{0},
+	}, {
+		Name:      "term_regexp_column",
+		Highlight: config.Highlight{Term: "/^(failed|cache)/", Field: "Message"},
+		Expected:  []int{1, 2},
+	}, {
+		Name:      "term_json_path",
+		Highlight: config.Highlight{Term: "other", Field: "$.tenant"},
+		Expected:  []int{1},
+	}, {
+		Name:      "level",
+		Highlight: config.Highlight{Level: "info"},
+		Expected:  []int{0, 1},
+	}, {
+		Name:      "greater",
+		Highlight: config.Highlight{Field: "$.duration_ms", Op: ">", Value: "1000"},
+		Expected:  []int{0},
+	}, {
+		Name:      "not_equal",
+		Highlight: config.Highlight{Field: "$.tenant", Op: "!=", Value: "acme"},
+		Expected:  []int{1},
+	}, {
+		Name:      "time",
+		Highlight: config.Highlight{Field: "Time", Op: ">=", Value: "2026-01-01T00:00:01Z"},
+		Expected:  []int{1, 2},
+	}, {
+		Name:      "all_conditions",
+		Highlight: config.Highlight{Level: "info", Field: "$.duration_ms", Op: "<", Value: "100"},
+		Expected:  []int{1},
+	}, {
+		Name:      "unknown_column",
+		Highlight: config.Highlight{Term: "a", Field: "Unknown"},
+		Expected:  []int{},
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.GetDefaultConfig()
+			entries := requireParseLogEntries(t, input, cfg)
+
+			highlighter, err := source.NewHighlighter([]config.Highlight{testCase.Highlight}, cfg)
+			require.NoError(t, err)
+
+			matched := []int{}
+
+			for i := range entries.Entries {
+				if len(highlighter.Match(entries.LogEntry(cfg, i))) > 0 {
+					matched = append(matched, i)
+				}
+			}
+
+			assert.Equal(t, testCase.Expected, matched)
+		})
+	}
+}
+
+func TestHighlighterMatchKind(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	cfg.Fields = []config.Field{
+		{Title: "Duration", Kind: config.FieldKindDuration, References: []string{"$.duration_ms"}},
+	}
+
+	entries := requireParseLogEntries(t, `{"duration_ms":1500}`+"\n"+`{"duration_ms":20}`+"\n", cfg)
+
+	// The value of the rule is parsed as a duration.
+	highlighter, err := source.NewHighlighter([]config.Highlight{{Field: "Duration", Op: ">", Value: "1s"}}, cfg)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, highlighter.Match(entries.LogEntry(cfg, 0)))
+	assert.Empty(t, highlighter.Match(entries.LogEntry(cfg, 1)))
+}
+
+func TestHighlighterMatchOrder(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	entries := requireParseLogEntries(t, `{"level":"error","message":"a"}`+"\n", cfg)
+
+	rules := []config.Highlight{
+		{Level: "warn", Style: config.ThemeStyle{Foreground: "1"}},
+		{Term: "b", Style: config.ThemeStyle{Foreground: "2"}},
+		{Term: "a", Column: "Message", Style: config.ThemeStyle{Bold: true}},
+	}
+
+	highlighter, err := source.NewHighlighter(rules, cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, []config.Highlight{rules[0], rules[2]}, highlighter.Match(entries.LogEntry(cfg, 0)))
+}
+
+func TestNewHighlighterInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := source.NewHighlighter([]config.Highlight{{Term: "/(/"}}, config.GetDefaultConfig())
+	require.ErrorIs(t, err, source.ErrInvalidHighlight)
+
+	_, err = source.NewHighlighter([]config.Highlight{{Field: "$[", Op: "==", Value: "1"}}, config.GetDefaultConfig())
+	require.ErrorIs(t, err, source.ErrInvalidHighlight)
+}
@@ -0,0 +1,366 @@
+package source
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+)
+
+const (
+	// inferenceEntries is the number of entries that are sampled to infer
+	// the schema.
+	inferenceEntries = 100
+	// inferenceLines limits the number of lines that are read while looking
+	// for entries, plain text lines are skipped.
+	inferenceLines = 10 * inferenceEntries
+	// maxExtraFields is the number of frequent fields that are proposed in
+	// addition to the time, the level and the message.
+	maxExtraFields = 3
+	// extraFieldWidth is the width of columns of frequent fields.
+	extraFieldWidth = 15
+	// maxTitleLength is the longest title that is allowed by the config.
+	maxTitleLength = 32
+)
+
+// Keys of the time, the level and the message in popular log formats, from
+// higher priority to lower priority. "@t", "@l" and "@mt" are used by
+// Serilog, "severity" and "textPayload" by Google Cloud Logging.
+var (
+	timeKeys = []string{
+		"timestamp", "time", "ts", "t", "@timestamp", "@t",
+		"datetime", "date", "eventTime", "receiveTimestamp",
+	}
+	levelKeys = []string{
+		"level", "lvl", "l", "severity", "@l", "loglevel", "log.level", "levelname",
+	}
+	messageKeys = []string{
+		"message", "msg", "@m", "@mt", "textPayload", "text", "log", "error", "err",
+	}
+)
+
+// identifierPattern matches keys that can be referenced with a dot.
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Schema is a proposal of columns that is inferred from the entries of a log.
+// The paths are ordered from the most frequent, they are empty if nothing is
+// found.
+type Schema struct {
+	// Time are the JSON paths of the time.
+	Time []string
+	// NumericTime is true if the time is a number, like a Unix timestamp.
+	NumericTime bool
+	// Level are the JSON paths of the level.
+	Level []string
+	// Message are the JSON paths of the message.
+	Message []string
+	// Extra are the top-level keys that are present in most of the entries.
+	Extra []string
+}
+
+// keyStats is a summary of the values of a key among the sampled entries.
+type keyStats struct {
+	path    []string
+	count   int
+	numbers int
+	times   int
+	scalars int
+	order   int
+}
+
+// InferSchema samples the first entries of the log and detects the keys of
+// the time, the level, the message and the frequent fields.
+func InferSchema(r io.Reader) Schema {
+	stats := map[string]*keyStats{}
+	entries := 0
+
+	reader := bufio.NewReader(r)
+
+	for range inferenceLines {
+		line, err := reader.ReadBytes('\n')
+
+		if parsedLine, _, ok := parseJSONObject(normalizeJSON(line)); ok {
+			collectKeyStats(stats, nil, parsedLine)
+
+			entries++
+		}
+
+		if err != nil || entries >= inferenceEntries {
+			break
+		}
+	}
+
+	if entries == 0 {
+		return Schema{}
+	}
+
+	keys := sortKeyStats(stats)
+
+	var schema Schema
+
+	used := map[string]bool{}
+
+	timeFound := findKeys(keys, timeKeys, used)
+	if len(timeFound) == 0 {
+		timeFound = findTimeValues(keys, used)
+	}
+
+	if len(timeFound) > 0 {
+		schema.Time = paths(timeFound)
+		schema.NumericTime = timeFound[0].numbers*2 > timeFound[0].count
+	}
+
+	schema.Level = paths(findKeys(keys, levelKeys, used))
+	schema.Message = paths(findKeys(keys, messageKeys, used))
+
+	for _, key := range keys {
+		if len(schema.Extra) == maxExtraFields || key.count*2 < entries {
+			break
+		}
+
+		if len(key.path) == 1 && key.scalars == key.count && !used[jsonPath(key.path)] &&
+			key.path[0] != PrefixFieldName {
+			schema.Extra = append(schema.Extra, key.path[0])
+		}
+	}
+
+	return schema
+}
+
+// ObservedKeys returns JSON paths of the keys of the first entries, from the
+// most frequent. Keys of nested objects are included one level deep.
+func (entries LazyLogEntries) ObservedKeys(cfg *config.Config) []string {
+	stats := map[string]*keyStats{}
+
+	for i := range min(entries.Len(), inferenceEntries) {
+		entry := entries.LogEntry(cfg, i)
+
+		if parsedLine, _, ok := parseJSONObject(entry.Content()); ok {
+			collectKeyStats(stats, nil, parsedLine)
+		}
+	}
+
+	return paths(sortKeyStats(stats))
+}
+
+// sortKeyStats returns the keys from the most frequent, keys with the same
+// frequency keep the order in which they are collected.
+func sortKeyStats(stats map[string]*keyStats) []*keyStats {
+	keys := make([]*keyStats, 0, len(stats))
+	for _, s := range stats {
+		keys = append(keys, s)
+	}
+
+	slices.SortFunc(keys, func(a, b *keyStats) int {
+		return cmp.Or(b.count-a.count, a.order-b.order)
+	})
+
+	return keys
+}
+
+// collectKeyStats counts top-level keys and keys of nested objects one
+// level deep, like "jsonPayload.message".
+func collectKeyStats(stats map[string]*keyStats, parent []string, object map[string]any) {
+	// Keys are sorted, so that the keys with the same frequency are always
+	// in the same order.
+	for _, name := range slices.Sorted(maps.Keys(object)) {
+		value := object[name]
+		path := append(slices.Clone(parent), name)
+		id := jsonPath(path)
+
+		s, ok := stats[id]
+		if !ok {
+			s = &keyStats{path: path, order: len(stats)}
+			stats[id] = s
+		}
+
+		s.count++
+
+		switch value := value.(type) {
+		case map[string]any:
+			if len(parent) == 0 {
+				collectKeyStats(stats, path, value)
+			}
+		case []any:
+			// Arrays are not shown in columns.
+		case json.Number:
+			s.numbers++
+			s.scalars++
+		case string:
+			if _, err := strconv.ParseFloat(value, 64); err == nil {
+				s.numbers++
+			} else if isTime(value) {
+				s.times++
+			}
+
+			s.scalars++
+		default:
+			s.scalars++
+		}
+	}
+}
+
+// findKeys returns the keys with one of the given names. More frequent keys
+// go first, then top-level keys, then keys with names of higher priority.
+func findKeys(keys []*keyStats, names []string, used map[string]bool) []*keyStats {
+	priority := func(key *keyStats) int {
+		return slices.Index(names, key.path[len(key.path)-1])
+	}
+
+	var found []*keyStats
+
+	for _, key := range keys {
+		if !used[jsonPath(key.path)] && key.scalars == key.count && priority(key) >= 0 {
+			found = append(found, key)
+		}
+	}
+
+	slices.SortStableFunc(found, func(a, b *keyStats) int {
+		return cmp.Or(b.count-a.count, len(a.path)-len(b.path), priority(a)-priority(b))
+	})
+
+	return markUsed(found, used)
+}
+
+// markUsed marks the keys as used and returns them.
+func markUsed(keys []*keyStats, used map[string]bool) []*keyStats {
+	for _, key := range keys {
+		used[jsonPath(key.path)] = true
+	}
+
+	return keys
+}
+
+// paths returns JSON paths of the keys.
+func paths(keys []*keyStats) []string {
+	var result []string
+
+	for _, key := range keys {
+		result = append(result, jsonPath(key.path))
+	}
+
+	return result
+}
+
+// findTimeValues returns the most frequent key that mostly holds timestamps.
+func findTimeValues(keys []*keyStats, used map[string]bool) []*keyStats {
+	for _, key := range keys {
+		if key.times*2 > key.count && !used[jsonPath(key.path)] {
+			return markUsed([]*keyStats{key}, used)
+		}
+	}
+
+	return nil
+}
+
+func isTime(value string) bool {
+	for _, layout := range []string{time.RFC3339Nano, time.DateTime, time.DateOnly} {
+		if _, err := time.Parse(layout, value); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// jsonPath returns a reference to the key, keys that are not identifiers are
+// quoted, like `$["@t"]`.
+func jsonPath(path []string) string {
+	result := "$"
+
+	for _, name := range path {
+		if identifierPattern.MatchString(name) {
+			result += "." + name
+		} else {
+			quoted, _ := json.Marshal(name)
+			result += "[" + string(quoted) + "]"
+		}
+	}
+
+	return result
+}
+
+// IsEmpty returns true if nothing is found.
+func (s Schema) IsEmpty() bool {
+	return len(s.Time) == 0 && len(s.Level) == 0 && len(s.Message) == 0 && len(s.Extra) == 0
+}
+
+// Fields returns the proposed columns.
+func (s Schema) Fields() []config.Field {
+	fields := make([]config.Field, 0, 3+len(s.Extra))
+
+	if len(s.Time) > 0 {
+		timeFormat := config.DefaultTimeFormat
+		kind := config.FieldKindTime
+
+		if s.NumericTime {
+			kind = config.FieldKindNumericTime
+		}
+
+		// nolint: mnd // The width of the default time column.
+		fields = append(fields, config.Field{
+			Title:      "Time",
+			Kind:       kind,
+			References: s.Time,
+			Width:      30,
+			TimeFormat: &timeFormat,
+		})
+	}
+
+	if len(s.Level) > 0 {
+		// nolint: mnd // The width of the default level column.
+		fields = append(fields, config.Field{
+			Title:      "Level",
+			Kind:       config.FieldKindLevel,
+			References: s.Level,
+			Width:      10,
+		})
+	}
+
+	for _, name := range s.Extra {
+		title := []rune(name)
+		if len(title) > maxTitleLength {
+			title = title[:maxTitleLength]
+		}
+
+		fields = append(fields, config.Field{
+			Title:      string(title),
+			Kind:       config.FieldKindAny,
+			References: []string{jsonPath([]string{name})},
+			Width:      extraFieldWidth,
+		})
+	}
+
+	if len(s.Message) > 0 {
+		fields = append(fields, config.Field{
+			Title:      "Message",
+			Kind:       config.FieldKindMessage,
+			References: s.Message,
+		})
+	}
+
+	return fields
+}
+
+// IsCoveredBy returns true if the fields already reference the most frequent
+// keys of the time, the level and the message.
+func (s Schema) IsCoveredBy(fields []config.Field) bool {
+	covered := func(paths []string, kinds ...config.FieldKind) bool {
+		return len(paths) == 0 || slices.ContainsFunc(fields, func(f config.Field) bool {
+			return slices.Contains(kinds, f.Kind) && slices.Contains(f.References, paths[0])
+		})
+	}
+
+	return covered(s.Time, config.FieldKindTime, config.FieldKindNumericTime,
+		config.FieldKindSecondTime, config.FieldKindMilliTime, config.FieldKindMicroTime,
+		config.FieldKindNanoTime) &&
+		covered(s.Level, config.FieldKindLevel) &&
+		covered(s.Message, config.FieldKindMessage)
+}
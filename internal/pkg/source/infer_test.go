@@ -0,0 +1,110 @@
+package source_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
+)
+
+func TestInferSchema(t *testing.T) {
+	t.Parallel()
+
+	testCases := [...]struct {
+		Name     string
+		Input    string
+		Expected source.Schema
+	}{{
+		Name: "serilog",
+		Input: `{"@t":"2026-01-01T00:00:00Z","@mt":"Hello {Name}","@l":"Warning","Name":"x"}
+{"@t":"2026-01-01T00:00:01Z","@mt":"Bye","Name":"y"}`,
+		Expected: source.Schema{
+			Time:    []string{`$["@t"]`},
+			Level:   []string{`$["@l"]`},
+			Message: []string{`$["@mt"]`},
+			Extra:   []string{"Name"},
+		},
+	}, {
+		Name: "gcp",
+		Input: `{"severity":"ERROR","textPayload":"boom","timestamp":"2026-01-01T00:00:00Z","resource":{"type":"k8s"}}
+{"severity":"INFO","jsonPayload":{"message":"ok"},"timestamp":"2026-01-01T00:00:01Z","resource":{"type":"k8s"}}`,
+		Expected: source.Schema{
+			Time:    []string{"$.timestamp"},
+			Level:   []string{"$.severity"},
+			Message: []string{"$.textPayload", "$.jsonPayload.message"},
+		},
+	}, {
+		Name: "numeric_time",
+		Input: `{"ts":1700000000,"msg":"first","error":"failed"}
+{"ts":1700000001,"msg":"second"}`,
+		Expected: source.Schema{
+			Time:        []string{"$.ts"},
+			NumericTime: true,
+			Message:     []string{"$.msg", "$.error"},
+		},
+	}, {
+		Name: "time_by_value",
+		Input: `starting
+{"created":"2026-01-01 10:00:00","event":"started","tags":["a"]}`,
+		Expected: source.Schema{
+			Time:  []string{"$.created"},
+			Extra: []string{"event"},
+		},
+	}, {
+		Name:     "plain_text",
+		Input:    "first\nsecond\n",
+		Expected: source.Schema{},
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			schema := source.InferSchema(strings.NewReader(testCase.Input))
+
+			assert.Equal(t, testCase.Expected, schema)
+			assert.Equal(t, len(testCase.Expected.Extra) == 0 && testCase.Expected.Time == nil, schema.IsEmpty())
+		})
+	}
+}
+
+func TestSchemaFields(t *testing.T) {
+	t.Parallel()
+
+	schema := source.Schema{
+		Time:    []string{`$["@t"]`},
+		Level:   []string{`$["@l"]`},
+		Message: []string{`$["@mt"]`},
+		Extra:   []string{"SourceContext"},
+	}
+
+	cfg := config.GetDefaultConfig()
+	cfg.Fields = schema.Fields()
+
+	assert.NoError(t, validator.New().Struct(cfg))
+	assert.False(t, schema.IsCoveredBy(config.GetDefaultConfig().Fields))
+	assert.True(t, schema.IsCoveredBy(cfg.Fields))
+
+	entries := requireParseLogEntries(t, `{"@t":"2026-01-01T00:00:00Z","@mt":"Hello","@l":"Warning","SourceContext":"App"}`+"\n", cfg)
+	assert.Equal(t, []string{"2026-01-01T00:00:00Z", "warn", "App", "Hello"}, entries.LogEntry(cfg, 0).Fields)
+
+	standard := source.InferSchema(strings.NewReader(`{"time":"1970-01-01T00:00:00Z","level":"info","message":"hi"}`))
+	assert.True(t, standard.IsCoveredBy(config.GetDefaultConfig().Fields))
+}
+
+func TestObservedKeys(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+
+	entries := requireParseLogEntries(t, `{"level":"info","message":"first","user":{"id":"u-1"}}
+{"level":"info","message":"second"}
+not json
+`, cfg)
+
+	assert.Equal(t, []string{"$.level", "$.message", "$.user", "$.user.id"}, entries.ObservedKeys(cfg))
+}
@@ -0,0 +1,292 @@
+package source
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+)
+
+// durationUnits are units of numeric durations by their names.
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+// parseDuration parses a number in the unit of the field or a Go duration,
+// like "1.5s".
+func parseDuration(value string, field config.Field) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		unit, ok := durationUnits[field.Unit]
+		if !ok {
+			unit = time.Millisecond
+		}
+
+		return time.Duration(number * float64(unit)), true
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return duration, true
+}
+
+// formatDuration renders durations shorter than a minute with the largest
+// unit and up to two decimal places, like "1.5s" or "850ms", and longer
+// durations rounded to seconds, like "1h2m3s".
+func formatDuration(duration time.Duration) string {
+	abs := duration.Abs()
+
+	var unit time.Duration
+
+	var suffix string
+
+	switch {
+	case abs >= time.Minute:
+		return duration.Round(time.Second).String()
+	case abs >= time.Second:
+		unit, suffix = time.Second, "s"
+	case abs >= time.Millisecond:
+		unit, suffix = time.Millisecond, "ms"
+	case abs >= time.Microsecond:
+		unit, suffix = time.Microsecond, "µs"
+	default:
+		unit, suffix = time.Nanosecond, "ns"
+	}
+
+	return trimFraction(strconv.FormatFloat(float64(duration)/float64(unit), 'f', 2, 64)) + suffix
+}
+
+// parseBytes parses a number of bytes or a size with a binary unit, like
+// "1.5MiB" or "10k".
+func parseBytes(value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		return number, true
+	}
+
+	size, err := units.RAMInBytes(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return float64(size), true
+}
+
+// parseNumber parses a number that can have thousands separators.
+func parseNumber(value string) (float64, bool) {
+	number, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(value), ",", ""), 64)
+	if err != nil || math.IsInf(number, 0) || math.IsNaN(number) {
+		return 0, false
+	}
+
+	return number, true
+}
+
+// formatNumber rounds the number to the precision and separates thousands
+// with commas.
+func formatNumber(number float64, precision *int) string {
+	formatted := strconv.FormatFloat(number, 'f', -1, 64)
+	if precision != nil {
+		formatted = strconv.FormatFloat(number, 'f', *precision, 64)
+	}
+
+	integer, fraction, hasFraction := strings.Cut(formatted, ".")
+	sign := ""
+
+	if strings.HasPrefix(integer, "-") {
+		sign, integer = "-", integer[1:]
+	}
+
+	var grouped strings.Builder
+
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+
+		grouped.WriteRune(digit)
+	}
+
+	if hasFraction {
+		return sign + grouped.String() + "." + fraction
+	}
+
+	return sign + grouped.String()
+}
+
+// parseBool parses "true", "false", "yes", "no", "1", "0" and other values
+// that strconv.ParseBool accepts.
+func parseBool(value string) (bool, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	switch value {
+	case "yes", "y", "on":
+		return true, true
+	case "no", "n", "off":
+		return false, true
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, false
+	}
+
+	return parsed, true
+}
+
+// formatJSON renders the value as compact JSON. Strings that hold encoded
+// objects or arrays are decoded.
+func formatJSON(value any) string {
+	if text, ok := value.(string); ok {
+		trimmed := strings.TrimSpace(text)
+
+		if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+			var compacted bytes.Buffer
+
+			if err := json.Compact(&compacted, []byte(trimmed)); err == nil {
+				return compacted.String()
+			}
+		}
+	}
+
+	var encoded bytes.Buffer
+
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(value); err != nil {
+		return "-"
+	}
+
+	return strings.TrimSuffix(encoded.String(), "\n")
+}
+
+// formatValue formats durations, bytes, numbers and booleans. Values that
+// can't be parsed are kept.
+func formatValue(value string, field config.Field) string {
+	switch field.Kind {
+	case config.FieldKindDuration:
+		if duration, ok := parseDuration(value, field); ok {
+			return formatDuration(duration)
+		}
+	case config.FieldKindBytes:
+		if size, ok := parseBytes(value); ok {
+			return units.BytesSize(size)
+		}
+	case config.FieldKindNumber:
+		if number, ok := parseNumber(value); ok {
+			return formatNumber(number, field.Precision)
+		}
+	case config.FieldKindBool:
+		if parsed, ok := parseBool(value); ok {
+			return strconv.FormatBool(parsed)
+		}
+	case config.FieldKindJSON:
+		return formatJSON(value)
+	}
+
+	return value
+}
+
+// trimFraction removes trailing zeros of the decimal part.
+func trimFraction(number string) string {
+	if !strings.Contains(number, ".") {
+		return number
+	}
+
+	return strings.TrimSuffix(strings.TrimRight(number, "0"), ".")
+}
+
+// comparisonOperators are checked in the order, so longer operators go
+// first.
+var comparisonOperators = []string{">=", "<=", "==", "!=", ">", "<"}
+
+// parseComparison splits the term like ">=1.5s" into the operator and the
+// value.
+func parseComparison(term string) (operator string, value string, ok bool) {
+	term = strings.TrimSpace(term)
+
+	for _, operator := range comparisonOperators {
+		if value, found := strings.CutPrefix(term, operator); found {
+			value = strings.TrimSpace(value)
+
+			return operator, value, value != ""
+		}
+	}
+
+	return "", "", false
+}
+
+// compareResult checks the result of the comparison with the operator.
+func compareResult(operator string, result int) bool {
+	switch operator {
+	case "==":
+		return result == 0
+	case "!=":
+		return result != 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	default:
+		return false
+	}
+}
+
+// newComparisonMatcher returns a predicate that compares values of the field
+// by their kind, if the term is a comparison, like ">=1.5s" for a duration or
+// "<1MiB" for bytes. Only durations, bytes, numbers and booleans are
+// compared.
+func newComparisonMatcher(term string, fieldName string, cfg *config.Config) (func(entry LogEntry) bool, bool) {
+	fieldIndex := getFilterFieldNameIndex(fieldName, cfg)
+	if fieldIndex < 0 {
+		return nil, false
+	}
+
+	field := cfg.Fields[fieldIndex]
+	if !field.IsNumeric() && field.Kind != config.FieldKindBool {
+		return nil, false
+	}
+
+	operator, value, ok := parseComparison(term)
+	if !ok {
+		return nil, false
+	}
+
+	target := textSortValue(value, field, nil)
+	if !target.numeric {
+		return nil, false
+	}
+
+	getValue, err := sortValueGetter(fieldName, cfg)
+	if err != nil {
+		return nil, false
+	}
+
+	return func(entry LogEntry) bool {
+		value := getValue(&sortEntry{LogEntry: entry})
+
+		return !value.missing && compareResult(operator, value.compare(target))
+	}, true
+}
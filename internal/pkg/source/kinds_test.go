@@ -0,0 +1,240 @@
+package source_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+)
+
+func TestFieldKindFormatting(t *testing.T) {
+	t.Parallel()
+
+	precision := 2
+
+	testCases := [...]struct {
+		Name     string
+		Field    config.Field
+		JSON     string
+		Expected string
+	}{{
+		Name:     "duration_milliseconds_by_default",
+		Field:    config.Field{Kind: config.FieldKindDuration},
+		JSON:     `{"value":1500}`,
+		Expected: "1.5s",
+	}, {
+		Name:     "duration_unit",
+		Field:    config.Field{Kind: config.FieldKindDuration, Unit: "ns"},
+		JSON:     `{"value":850000}`,
+		Expected: "850µs",
+	}, {
+		Name:     "duration_string",
+		Field:    config.Field{Kind: config.FieldKindDuration},
+		JSON:     `{"value":"3723.4s"}`,
+		Expected: "1h2m3s",
+	}, {
+		Name:     "duration_invalid",
+		Field:    config.Field{Kind: config.FieldKindDuration},
+		JSON:     `{"value":"soon"}`,
+		Expected: "soon",
+	}, {
+		Name:     "bytes",
+		Field:    config.Field{Kind: config.FieldKindBytes},
+		JSON:     `{"value":1572864}`,
+		Expected: "1.5MiB",
+	}, {
+		Name:     "bytes_string",
+		Field:    config.Field{Kind: config.FieldKindBytes},
+		JSON:     `{"value":"2k"}`,
+		Expected: "2KiB",
+	}, {
+		Name:     "number",
+		Field:    config.Field{Kind: config.FieldKindNumber},
+		JSON:     `{"value":-1234567.125}`,
+		Expected: "-1,234,567.125",
+	}, {
+		Name:     "number_precision",
+		Field:    config.Field{Kind: config.FieldKindNumber, Precision: &precision},
+		JSON:     `{"value":"1234.5678"}`,
+		Expected: "1,234.57",
+	}, {
+		Name:     "number_invalid",
+		Field:    config.Field{Kind: config.FieldKindNumber},
+		JSON:     `{"value":"NaN"}`,
+		Expected: "NaN",
+	}, {
+		Name:     "bool",
+		Field:    config.Field{Kind: config.FieldKindBool},
+		JSON:     `{"value":true}`,
+		Expected: "true",
+	}, {
+		Name:     "bool_string",
+		Field:    config.Field{Kind: config.FieldKindBool},
+		JSON:     `{"value":"No"}`,
+		Expected: "false",
+	}, {
+		Name:     "json_object",
+		Field:    config.Field{Kind: config.FieldKindJSON},
+		JSON:     `{"value":{"b":[1, 2],"a":"<x>"}}`,
+		Expected: `{"a":"<x>","b":[1,2]}`,
+	}, {
+		Name:     "json_encoded_object",
+		Field:    config.Field{Kind: config.FieldKindJSON},
+		JSON:     `{"value":"{ \"id\": 1 }"}`,
+		Expected: `{"id":1}`,
+	}, {
+		Name:     "json_string",
+		Field:    config.Field{Kind: config.FieldKindJSON},
+		JSON:     `{"value":"text"}`,
+		Expected: `"text"`,
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.GetDefaultConfig()
+
+			field := testCase.Field
+			field.Title = "Value"
+			field.References = []string{"$.value"}
+			cfg.Fields = []config.Field{field}
+
+			actual := parseTableRow(t, testCase.JSON, cfg)
+			assert.Equal(t, testCase.Expected, actual[0])
+		})
+	}
+}
+
+func TestLazyLogEntriesSortKinds(t *testing.T) {
+	t.Parallel()
+
+	const input = `{"duration":"1.5s","size":"1KiB","count":"1,000","ok":true}
+{"duration":200,"size":2048,"count":20,"ok":false}
+{"duration":"2m","size":"1m","count":3.5,"ok":"yes"}
+`
+
+	cfg := config.GetDefaultConfig()
+	cfg.Fields = []config.Field{
+		{Title: "Duration", Kind: config.FieldKindDuration, References: []string{"$.duration"}},
+		{Title: "Size", Kind: config.FieldKindBytes, References: []string{"$.size"}},
+		{Title: "Count", Kind: config.FieldKindNumber, References: []string{"$.count"}},
+		{Title: "OK", Kind: config.FieldKindBool, References: []string{"$.ok"}},
+	}
+
+	testCases := [...]struct {
+		Field    string
+		Expected []int
+	}{{
+		Field:    "Duration",
+		Expected: []int{1, 0, 2},
+	}, {
+		Field:    "Size",
+		Expected: []int{0, 1, 2},
+	}, {
+		Field:    "Count",
+		Expected: []int{2, 1, 0},
+	}, {
+		Field:    "OK",
+		Expected: []int{1, 0, 2},
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Field, func(t *testing.T) {
+			t.Parallel()
+
+			entries := requireParseLogEntries(t, input, cfg)
+
+			sorted, err := entries.Sort(config.Sort{Field: testCase.Field}, cfg)
+			require.NoError(t, err)
+
+			indexes := make([]int, 0, sorted.Len())
+			for _, entry := range sorted.Entries {
+				indexes = append(indexes, entry.Index())
+			}
+
+			assert.Equal(t, testCase.Expected, indexes)
+		})
+	}
+}
+
+func TestLazyLogEntriesFilterComparison(t *testing.T) {
+	t.Parallel()
+
+	const input = `{"duration":"1.5s","size":1024,"count":"1,000","ok":true,"message":">1s"}
+{"duration":200,"size":2048,"count":20,"ok":false,"message":"fast"}
+{"duration":"2m","size":"1m","count":3.5,"ok":"yes","message":"slow"}
+{"message":"no values"}
+`
+
+	cfg := config.GetDefaultConfig()
+	cfg.Fields = []config.Field{
+		{Title: "Duration", Kind: config.FieldKindDuration, References: []string{"$.duration"}},
+		{Title: "Size", Kind: config.FieldKindBytes, References: []string{"$.size"}},
+		{Title: "Count", Kind: config.FieldKindNumber, References: []string{"$.count"}},
+		{Title: "OK", Kind: config.FieldKindBool, References: []string{"$.ok"}},
+		{Title: "Message", Kind: config.FieldKindMessage, References: []string{"$.message"}},
+	}
+
+	testCases := [...]struct {
+		Name     string
+		Term     string
+		Field    string
+		Expected []int
+	}{{
+		Name:     "duration",
+		Term:     ">1s",
+		Field:    "Duration",
+		Expected: []int{0, 2},
+	}, {
+		Name:     "duration_number_in_unit",
+		Term:     "<= 200",
+		Field:    "Duration",
+		Expected: []int{1},
+	}, {
+		Name:     "bytes",
+		Term:     ">=2KiB",
+		Field:    "Size",
+		Expected: []int{1, 2},
+	}, {
+		Name:     "number",
+		Term:     "!=20",
+		Field:    "Count",
+		Expected: []int{0, 2},
+	}, {
+		Name:     "bool",
+		Term:     "==true",
+		Field:    "OK",
+		Expected: []int{0, 2},
+	}, {
+		Name:     "not_a_value",
+		Term:     ">fast",
+		Field:    "Duration",
+		Expected: []int{},
+	}, {
+		Name:     "substring_of_message",
+		Term:     ">1s",
+		Field:    "Message",
+		Expected: []int{0},
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			entries := requireParseLogEntries(t, input, cfg)
+
+			filtered, err := entries.Filter(testCase.Term, testCase.Field, cfg)
+			require.NoError(t, err)
+
+			indexes := make([]int, 0, filtered.Len())
+			for _, entry := range filtered.Entries {
+				indexes = append(indexes, entry.Index())
+			}
+
+			assert.Equal(t, testCase.Expected, indexes)
+		})
+	}
+}
@@ -1,6 +1,12 @@
 package source
 
-import "strings"
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+)
 
 // Level of the logs entity.
 type Level string
@@ -39,6 +45,40 @@ func ParseLevel(value string, customMapping map[string]string) Level {
 	}
 }
 
+// ParseConfigLevel parses level from the text value by levels of the config.
+// The value is matched with names, aliases and numeric ranges of levels in
+// their order. Built-in levels are used if the config doesn't declare levels.
+func ParseConfigLevel(value string, cfg *config.Config) Level {
+	if len(cfg.Levels) == 0 {
+		return ParseLevel(value, cfg.CustomLevelMapping)
+	}
+
+	value = strings.ToLower(value)
+	value = strings.TrimSpace(value)
+
+	if customLevel, ok := cfg.CustomLevelMapping[value]; ok {
+		value = strings.ToLower(customLevel)
+	}
+
+	if value == "" {
+		return LevelUnknown
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	isNumber := err == nil
+
+	for _, level := range cfg.Levels {
+		switch {
+		case strings.EqualFold(level.Name, value),
+			slices.ContainsFunc(level.Aliases, func(alias string) bool { return strings.EqualFold(alias, value) }),
+			isNumber && len(level.Range) == 2 && number >= level.Range[0] && number <= level.Range[1]:
+			return Level(strings.ToLower(level.Name))
+		}
+	}
+
+	return Level(value)
+}
+
 // String implement fmt.Stringer interface.
 func (l Level) String() string {
 	return strings.ToLower(string(l))
@@ -55,3 +95,39 @@ const (
 	LevelPanic   Level = "panic"
 	LevelFatal   Level = "fatal"
 )
+
+// levelSeverities orders known levels from the least severe.
+var levelSeverities = map[Level]int{
+	LevelTrace:   1,
+	LevelDebug:   2,
+	LevelInfo:    3,
+	LevelWarning: 4,
+	LevelError:   5,
+	LevelPanic:   6,
+	LevelFatal:   7,
+}
+
+// Severity returns the position of the level from the least severe. Levels
+// of the config replace built-in levels if they are declared.
+func (l Level) Severity(cfg *config.Config) (int, bool) {
+	if cfg == nil || len(cfg.Levels) == 0 {
+		severity, ok := levelSeverities[l]
+
+		return severity, ok
+	}
+
+	index := slices.IndexFunc(cfg.Levels, func(level config.Level) bool {
+		return strings.EqualFold(level.Name, string(l))
+	})
+
+	return index + 1, index >= 0
+}
+
+// AtLeast returns true if the level is as severe as the minimum level or more.
+// Unknown levels are never severe enough.
+func (l Level) AtLeast(minimum Level, cfg *config.Config) bool {
+	severity, ok := l.Severity(cfg)
+	minimumSeverity, _ := minimum.Severity(cfg)
+
+	return ok && severity >= minimumSeverity
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
 	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
@@ -80,3 +81,129 @@ func TestParseLevel(t *testing.T) {
 		})
 	}
 }
+
+func TestLevelAtLeast(t *testing.T) {
+	t.Parallel()
+
+	testCases := [...]struct {
+		Level    source.Level
+		Minimum  source.Level
+		Expected bool
+	}{
+		{Level: source.LevelError, Minimum: source.LevelWarning, Expected: true},
+		{Level: source.LevelWarning, Minimum: source.LevelWarning, Expected: true},
+		{Level: source.LevelInfo, Minimum: source.LevelWarning, Expected: false},
+		{Level: source.LevelFatal, Minimum: source.LevelPanic, Expected: true},
+		{Level: source.LevelUnknown, Minimum: source.LevelTrace, Expected: false},
+		{Level: "custom", Minimum: source.LevelTrace, Expected: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(string(testCase.Level)+"_"+string(testCase.Minimum), func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.Expected, testCase.Level.AtLeast(testCase.Minimum, nil))
+		})
+	}
+}
+
+// syslogLevels are syslog severities from the least severe.
+func syslogLevels() *config.Config {
+	cfg := config.GetDefaultConfig()
+	cfg.CustomLevelMapping = map[string]string{"warning": "warn"}
+	cfg.Levels = []config.Level{
+		{Name: "debug", Range: []float64{7, 7}},
+		{Name: "info", Range: []float64{6, 6}},
+		{Name: "notice", Range: []float64{5, 5}},
+		{Name: "warn", Range: []float64{4, 4}},
+		{Name: "error", Aliases: []string{"err"}, Range: []float64{3, 3}},
+		{Name: "critical", Aliases: []string{"crit"}, Range: []float64{2, 2}},
+		{Name: "alert", Range: []float64{1, 1}},
+		{Name: "emergency", Aliases: []string{"emerg"}, Range: []float64{0, 0}},
+	}
+
+	return cfg
+}
+
+func TestParseConfigLevel(t *testing.T) {
+	t.Parallel()
+
+	testCases := [...]struct {
+		Input    string
+		Expected source.Level
+	}{
+		{Input: "", Expected: source.LevelUnknown},
+		{Input: "NOTICE", Expected: "notice"},
+		{Input: "Emergency", Expected: "emergency"},
+		{Input: "crit", Expected: "critical"},
+		{Input: "warning", Expected: "warn"},
+		{Input: "5", Expected: "notice"},
+		{Input: "0", Expected: "emergency"},
+		{Input: "8", Expected: "8"},
+		// The first letter doesn't match built-in levels.
+		{Input: "trace", Expected: "trace"},
+		{Input: "e", Expected: "e"},
+	}
+
+	cfg := syslogLevels()
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Input, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.Expected, source.ParseConfigLevel(testCase.Input, cfg))
+		})
+	}
+
+	t.Run("builtin", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.GetDefaultConfig()
+		assert.Equal(t, source.LevelWarning, source.ParseConfigLevel("WRN", cfg))
+		assert.Equal(t, source.LevelInfo, source.ParseConfigLevel("30", cfg))
+	})
+}
+
+func TestLevelAtLeastConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := syslogLevels()
+
+	assert.True(t, source.Level("alert").AtLeast("critical", cfg))
+	assert.True(t, source.Level("notice").AtLeast("notice", cfg))
+	assert.False(t, source.Level("notice").AtLeast("warn", cfg))
+	assert.False(t, source.LevelTrace.AtLeast("debug", cfg))
+
+	severity, ok := source.Level("EMERGENCY").Severity(cfg)
+	assert.True(t, ok)
+	assert.Equal(t, 8, severity)
+}
+
+func TestFilterByLevelConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := syslogLevels()
+
+	const input = `{"level":5,"message":"a"}
+{"level":"crit","message":"b"}
+{"level":"info","message":"c"}
+{"level":"emerg","message":"d"}
+`
+
+	entries := requireParseLogEntries(t, input, cfg)
+
+	filtered, err := entries.FilterByLevel("critical", cfg)
+	require.NoError(t, err)
+
+	messages := make([]string, 0, filtered.Len())
+	for i := range filtered.Entries {
+		messages = append(messages, filtered.LogEntry(cfg, i).Fields[2])
+	}
+
+	assert.Equal(t, []string{"b", "d"}, messages)
+
+	sorted, err := entries.Sort(config.Sort{Field: "Level", Descending: true}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "emergency", sorted.LogEntry(cfg, 0).Fields[1])
+	assert.Equal(t, "info", sorted.LogEntry(cfg, 3).Fields[1])
+}
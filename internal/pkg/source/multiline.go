@@ -0,0 +1,118 @@
+package source
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+)
+
+// multilineRules decide whether a line continues the previous entry.
+type multilineRules struct {
+	indentation  bool
+	startPattern *regexp.Regexp
+	json         bool
+}
+
+func newMultilineRules(cfg config.Multiline) (*multilineRules, error) {
+	if !cfg.IsEnabled() {
+		return nil, nil //nolint:nilnil // Rules are disabled.
+	}
+
+	rules := &multilineRules{
+		indentation: cfg.Indentation,
+		json:        cfg.JSON,
+	}
+
+	if cfg.StartPattern != "" {
+		exp, err := regexp.Compile(cfg.StartPattern)
+		if err != nil {
+			return nil, fmt.Errorf("compiling multiline start pattern: %w", err)
+		}
+
+		rules.startPattern = exp
+	}
+
+	return rules, nil
+}
+
+// multilineEntry is an entry that is being assembled from several lines.
+type multilineEntry struct {
+	rules *multilineRules
+
+	// isJSON is true if the entry starts with "{".
+	isJSON  bool
+	balance jsonBalance
+}
+
+func (r *multilineRules) newEntry(firstLine []byte) *multilineEntry {
+	entry := &multilineEntry{
+		rules:  r,
+		isJSON: r.json && bytes.HasPrefix(bytes.TrimSpace(firstLine), []byte("{")),
+	}
+
+	if entry.isJSON {
+		entry.balance = entry.balance.feed(firstLine)
+	}
+
+	return entry
+}
+
+// continues returns true if the line belongs to the entry.
+func (e *multilineEntry) continues(line []byte) bool {
+	switch {
+	case e.isJSON && e.balance.isOpen():
+		return true
+	case e.rules.indentation && len(line) > 0 && (line[0] == ' ' || line[0] == '\t'):
+		return true
+	case e.rules.startPattern != nil && !e.rules.startPattern.Match(bytes.TrimRight(line, "\r\n")):
+		return true
+	default:
+		return false
+	}
+}
+
+// append adds the line to the entry.
+func (e *multilineEntry) append(line []byte) {
+	if e.isJSON {
+		e.balance = e.balance.feed(line)
+	}
+}
+
+// jsonBalance tracks unclosed braces and brackets of a JSON text. Braces
+// inside string literals are ignored.
+type jsonBalance struct {
+	depth    int
+	inString bool
+	escaped  bool
+}
+
+func (b jsonBalance) isOpen() bool {
+	return b.depth > 0
+}
+
+// feed processes the next line of the text.
+func (b jsonBalance) feed(data []byte) jsonBalance {
+	for _, c := range data {
+		switch {
+		case b.escaped:
+			b.escaped = false
+		case b.inString && c == '\\':
+			b.escaped = true
+		case c == '"':
+			b.inString = !b.inString
+		case b.inString:
+		case c == '{', c == '[':
+			b.depth++
+		case c == '}', c == ']':
+			b.depth--
+		}
+	}
+
+	// A string literal can't hold a line break, so it is malformed.
+	b.inString = false
+	b.escaped = false
+
+	return b
+}
@@ -0,0 +1,392 @@
+package source
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yalp/jsonpath"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+)
+
+// Sort returns entries that are ordered by the column with the title or by
+// the JSONPath. Entries with equal values keep the order of the log, entries
+// without the value are always the last.
+//
+// Times are compared as instants, levels by their severity, durations,
+// bytes and numbers by their values and booleans as false before true.
+// Other values are compared as text.
+func (entries LazyLogEntries) Sort(order config.Sort, cfg *config.Config) (LazyLogEntries, error) {
+	getValue, err := sortValueGetter(order.Field, cfg)
+	if err != nil {
+		return LazyLogEntries{}, err
+	}
+
+	values := make([]sortValue, entries.Len())
+	for i := range entries.Entries {
+		values[i] = getValue(&sortEntry{LogEntry: entries.LogEntry(cfg, i)})
+	}
+
+	permutation := make([]int, entries.Len())
+	for i := range permutation {
+		permutation[i] = i
+	}
+
+	slices.SortStableFunc(permutation, func(a, b int) int {
+		return compareSortValues(values[a], values[b], order)
+	})
+
+	sorted := make([]LazyLogEntry, 0, len(permutation))
+	for _, i := range permutation {
+		sorted = append(sorted, entries.Entries[i])
+	}
+
+	return LazyLogEntries{
+		Seeker:  entries.Seeker,
+		Entries: sorted,
+	}, nil
+}
+
+// Merge returns the sorted entries together with the added ones in the
+// order. Only the added entries are sorted, their positions are found by
+// binary search, so new entries of the followed log don't sort it again.
+// Entries with equal values keep the order of the log if the added entries
+// follow the sorted ones in the log.
+func (entries LazyLogEntries) Merge(
+	added LazyLogEntries,
+	order config.Sort,
+	cfg *config.Config,
+) (LazyLogEntries, error) {
+	getValue, err := sortValueGetter(order.Field, cfg)
+	if err != nil {
+		return LazyLogEntries{}, err
+	}
+
+	added, err = added.Sort(order, cfg)
+	if err != nil {
+		return LazyLogEntries{}, err
+	}
+
+	merged := make([]LazyLogEntry, 0, entries.Len()+added.Len())
+	start := 0
+
+	for i, entry := range added.Entries {
+		value := getValue(&sortEntry{LogEntry: added.LogEntry(cfg, i)})
+
+		position := start + sort.Search(entries.Len()-start, func(j int) bool {
+			return compareSortValues(getValue(&sortEntry{LogEntry: entries.LogEntry(cfg, start+j)}), value, order) > 0
+		})
+
+		merged = append(merged, entries.Entries[start:position]...)
+		merged = append(merged, entry)
+		start = position
+	}
+
+	merged = append(merged, entries.Entries[start:]...)
+
+	return LazyLogEntries{
+		Seeker:  entries.Seeker,
+		Entries: merged,
+	}, nil
+}
+
+// compareSortValues orders values, missing values are always the last.
+func compareSortValues(a, b sortValue, order config.Sort) int {
+	if a.missing || b.missing {
+		return compareBool(a.missing, b.missing)
+	}
+
+	if order.Descending {
+		return b.compare(a)
+	}
+
+	return a.compare(b)
+}
+
+// sortValue is a comparable value of the entry.
+type sortValue struct {
+	missing bool
+	numeric bool
+	number  float64
+	// exact is true if the number is also kept as integer, because
+	// nanoseconds of times and large integers don't fit into float64.
+	exact   bool
+	integer int64
+	text    string
+}
+
+// integerSortValue returns the numeric value of the integer.
+func integerSortValue(integer int64) sortValue {
+	return sortValue{numeric: true, number: float64(integer), exact: true, integer: integer}
+}
+
+// compare orders numbers before text.
+func (v sortValue) compare(other sortValue) int {
+	switch {
+	case v.exact && other.exact:
+		return cmp.Compare(v.integer, other.integer)
+	case v.numeric && other.numeric:
+		return cmp.Compare(v.number, other.number)
+	case v.numeric != other.numeric:
+		return compareBool(other.numeric, v.numeric)
+	default:
+		return strings.Compare(v.text, other.text)
+	}
+}
+
+// compareBool orders false before true.
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// sortEntry is an entry with its JSON object, which is parsed on the first
+// use, so values of several fields are read from a single parsed object.
+type sortEntry struct {
+	LogEntry
+
+	parsed bool
+	object map[string]any
+}
+
+// jsonObject returns the JSON object of the entry.
+func (e *sortEntry) jsonObject() (map[string]any, bool) {
+	if !e.parsed {
+		e.parsed = true
+		e.object, _, _ = parseJSONObject(e.Content())
+	}
+
+	return e.object, e.object != nil
+}
+
+// sortValueGetter returns a function that gets the value of the entry by the
+// JSONPath or by the title of the column.
+func sortValueGetter(fieldName string, cfg *config.Config) (func(entry *sortEntry) sortValue, error) {
+	layouts := sortTimeLayouts(cfg)
+
+	if strings.HasPrefix(fieldName, "$") {
+		read, err := jsonpath.Prepare(fieldName)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidSort, fieldName, err)
+		}
+
+		readers := []jsonpath.FilterFunc{read}
+
+		return func(entry *sortEntry) sortValue {
+			return jsonSortValue(entry, readers, config.Field{Kind: config.FieldKindAny}, layouts)
+		}, nil
+	}
+
+	fieldIndex := getFilterFieldNameIndex(fieldName, cfg)
+	if fieldIndex < 0 {
+		return nil, fmt.Errorf("%w: unknown field: %s", ErrInvalidSort, fieldName)
+	}
+
+	field := cfg.Fields[fieldIndex]
+	readers := prepareReferences(field.References)
+
+	return func(entry *sortEntry) sortValue {
+		if fieldIndex >= len(entry.Fields) || entry.Fields[fieldIndex] == "-" {
+			return sortValue{missing: true}
+		}
+
+		rendered := entry.Fields[fieldIndex]
+
+		switch field.Kind {
+		case config.FieldKindLevel:
+			severity, ok := ParseConfigLevel(rendered, cfg).Severity(cfg)
+			if !ok {
+				return sortValue{missing: true}
+			}
+
+			return integerSortValue(int64(severity))
+		case config.FieldKindAny, config.FieldKindMessage:
+			return textSortValue(rendered, field, layouts)
+		default:
+			// The rendered time could be truncated to seconds and rendered
+			// numbers could be rounded, so the raw value is compared if it
+			// can be found.
+			if value := jsonSortValue(entry, readers, field, layouts); !value.missing {
+				return value
+			}
+
+			return textSortValue(rendered, field, layouts)
+		}
+	}, nil
+}
+
+// prepareReferences prepares JSONPaths of references, invalid ones are
+// skipped.
+func prepareReferences(refs []string) []jsonpath.FilterFunc {
+	readers := make([]jsonpath.FilterFunc, 0, len(refs))
+
+	for _, ref := range refs {
+		if read, err := jsonpath.Prepare(ref); err == nil {
+			readers = append(readers, read)
+		}
+	}
+
+	return readers
+}
+
+// jsonSortValue returns the first value of the entry that is found by the
+// references.
+func jsonSortValue(entry *sortEntry, readers []jsonpath.FilterFunc, field config.Field, layouts []string) sortValue {
+	parsedLine, ok := entry.jsonObject()
+	if !ok {
+		return sortValue{missing: true}
+	}
+
+	for _, read := range readers {
+		found, err := read(parsedLine)
+		if err != nil || found == nil {
+			continue
+		}
+
+		switch value := found.(type) {
+		case string:
+			return textSortValue(value, field, layouts)
+		case json.Number:
+			number, err := value.Float64()
+			if err != nil {
+				return sortValue{text: value.String()}
+			}
+
+			return numberSortValue(number, value.String(), field)
+		case bool:
+			return textSortValue(strconv.FormatBool(value), field, layouts)
+		default:
+			text, err := json.Marshal(value)
+			if err != nil {
+				continue
+			}
+
+			return sortValue{text: string(text)}
+		}
+	}
+
+	return sortValue{missing: true}
+}
+
+// textSortValue converts numbers, times and values of the kind of the field
+// in the text to numbers.
+func textSortValue(value string, field config.Field, layouts []string) sortValue {
+	value = strings.TrimSpace(value)
+
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		return numberSortValue(number, value, field)
+	}
+
+	if number, ok := kindSortValue(value, field); ok {
+		return number
+	}
+
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return integerSortValue(parsed.UnixNano())
+		}
+	}
+
+	return sortValue{text: value}
+}
+
+// sortField returns the column with the title, or a field of any kind for a
+// JSONPath or an unknown title.
+func sortField(fieldName string, cfg *config.Config) config.Field {
+	if fieldIndex := getFilterFieldNameIndex(fieldName, cfg); fieldIndex >= 0 {
+		return cfg.Fields[fieldIndex]
+	}
+
+	return config.Field{Kind: config.FieldKindAny}
+}
+
+// sortTimeLayouts returns layouts of times in logs and of rendered times.
+func sortTimeLayouts(cfg *config.Config) []string {
+	layouts := append([]string{time.RFC3339Nano}, cfg.TimeLayouts...)
+
+	for _, f := range cfg.Fields {
+		if f.TimeFormat != nil {
+			layouts = append(layouts, *f.TimeFormat)
+		}
+	}
+
+	return layouts
+}
+
+// kindSortValue parses durations to nanoseconds, sizes to bytes, numbers
+// with thousands separators and booleans to 0 and 1.
+func kindSortValue(value string, field config.Field) (sortValue, bool) {
+	switch field.Kind {
+	case config.FieldKindDuration:
+		duration, ok := parseDuration(value, field)
+
+		return integerSortValue(int64(duration)), ok
+	case config.FieldKindBytes:
+		size, ok := parseBytes(value)
+
+		return sortValue{numeric: true, number: size}, ok
+	case config.FieldKindNumber:
+		number, ok := parseNumber(value)
+
+		return sortValue{numeric: true, number: number}, ok
+	case config.FieldKindBool:
+		parsed, ok := parseBool(value)
+		if !ok {
+			return sortValue{}, false
+		}
+
+		if parsed {
+			return integerSortValue(1), true
+		}
+
+		return integerSortValue(0), true
+	default:
+		return sortValue{}, false
+	}
+}
+
+// numberSortValue converts epoch times to nanoseconds, so they can be
+// compared with parsed times, and numeric durations to nanoseconds. Integers
+// are compared exactly.
+func numberSortValue(number float64, text string, field config.Field) sortValue {
+	kind := field.Kind
+
+	if kind == config.FieldKindNumericTime {
+		kind = guessTimeFieldKind(text)
+	}
+
+	unit := int64(1)
+
+	switch kind {
+	case config.FieldKindSecondTime:
+		unit = int64(time.Second)
+	case config.FieldKindMilliTime:
+		unit = int64(time.Millisecond)
+	case config.FieldKindMicroTime:
+		unit = int64(time.Microsecond)
+	case config.FieldKindDuration:
+		if duration, ok := parseDuration(text, field); ok {
+			return integerSortValue(int64(duration))
+		}
+	}
+
+	integer, err := strconv.ParseInt(text, 10, 64)
+	if err == nil && integer <= math.MaxInt64/unit && integer >= math.MinInt64/unit {
+		return integerSortValue(integer * unit)
+	}
+
+	return sortValue{numeric: true, number: number * float64(unit)}
+}
@@ -0,0 +1,154 @@
+package source_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
+)
+
+func TestLazyLogEntriesSort(t *testing.T) {
+	t.Parallel()
+
+	// The times differ in milliseconds and in representations, the default
+	// time format renders only seconds.
+	const input = `{"time":"2026-01-01T00:00:00.300Z","level":"info","message":"b","duration":20}
+{"time":1767225600100,"level":"error","message":"c","duration":3}
+{"level":"debug","message":"a"}
+{"time":"2026-01-01T00:00:00.200Z","level":"warn","message":"d","duration":100}
+`
+
+	testCases := [...]struct {
+		Name     string
+		Sort     config.Sort
+		Expected []int
+	}{{
+		Name:     "time",
+		Sort:     config.Sort{Field: "Time"},
+		Expected: []int{1, 3, 0, 2},
+	}, {
+		Name:     "time_descending",
+		Sort:     config.Sort{Field: "time", Descending: true},
+		Expected: []int{0, 3, 1, 2},
+	}, {
+		Name:     "level",
+		Sort:     config.Sort{Field: "Level", Descending: true},
+		Expected: []int{1, 3, 0, 2},
+	}, {
+		Name:     "message",
+		Sort:     config.Sort{Field: "Message"},
+		Expected: []int{2, 0, 1, 3},
+	}, {
+		Name:     "json_path",
+		Sort:     config.Sort{Field: "$.duration"},
+		Expected: []int{1, 0, 3, 2},
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.GetDefaultConfig()
+
+			entries := requireParseLogEntries(t, input, cfg)
+
+			sorted, err := entries.Sort(testCase.Sort, cfg)
+			require.NoError(t, err)
+
+			indexes := make([]int, 0, sorted.Len())
+			for _, entry := range sorted.Entries {
+				indexes = append(indexes, entry.Index())
+			}
+
+			assert.Equal(t, testCase.Expected, indexes)
+
+			// The original order is not changed.
+			assert.Equal(t, 0, entries.Entries[0].Index())
+
+			// Appended entries are merged into the same order.
+			for split := range entries.Len() {
+				head := source.LazyLogEntries{Seeker: entries.Seeker, Entries: entries.Entries[:split]}
+				tail := source.LazyLogEntries{Seeker: entries.Seeker, Entries: entries.Entries[split:]}
+
+				head, err = head.Sort(testCase.Sort, cfg)
+				require.NoError(t, err)
+
+				merged, err := head.Merge(tail, testCase.Sort, cfg)
+				require.NoError(t, err)
+				assert.Equal(t, sorted.Entries, merged.Entries, split)
+			}
+		})
+	}
+}
+
+func TestLazyLogEntriesSortExact(t *testing.T) {
+	t.Parallel()
+
+	// Times differ in nanoseconds and identifiers differ beyond the precision
+	// of float64.
+	const input = `{"time":"2026-01-01T00:00:00.000000003Z","id":9007199254740993}
+{"time":1767225600000000001,"id":9007199254740992}
+{"time":"2026-01-01T00:00:00.000000002Z","id":9007199254740994}
+`
+
+	cfg := config.GetDefaultConfig()
+	entries := requireParseLogEntries(t, input, cfg)
+
+	for order, expected := range map[string][]int{
+		"Time": {1, 2, 0},
+		"$.id": {1, 0, 2},
+	} {
+		sorted, err := entries.Sort(config.Sort{Field: order}, cfg)
+		require.NoError(t, err)
+
+		indexes := make([]int, 0, sorted.Len())
+		for _, entry := range sorted.Entries {
+			indexes = append(indexes, entry.Index())
+		}
+
+		assert.Equal(t, expected, indexes, order)
+	}
+}
+
+func TestLazyLogEntriesMergeEqual(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	entries := requireParseLogEntries(t, `{"level":"info","message":"a"}
+{"level":"error","message":"b"}
+{"level":"info","message":"c"}
+{"level":"error","message":"d"}
+`, cfg)
+
+	order := config.Sort{Field: "Level"}
+
+	head, err := source.LazyLogEntries{Seeker: entries.Seeker, Entries: entries.Entries[:2]}.Sort(order, cfg)
+	require.NoError(t, err)
+
+	merged, err := head.Merge(source.LazyLogEntries{Seeker: entries.Seeker, Entries: entries.Entries[2:]}, order, cfg)
+	require.NoError(t, err)
+
+	indexes := make([]int, 0, merged.Len())
+	for _, entry := range merged.Entries {
+		indexes = append(indexes, entry.Index())
+	}
+
+	// Entries with equal values keep the order of the log.
+	assert.Equal(t, []int{0, 2, 1, 3}, indexes)
+}
+
+func TestLazyLogEntriesSortInvalid(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	entries := requireParseLogEntries(t, `{"message":"a"}`+"\n", cfg)
+
+	_, err := entries.Sort(config.Sort{Field: "Unknown"}, cfg)
+	require.ErrorIs(t, err, source.ErrInvalidSort)
+
+	_, err = entries.Sort(config.Sort{Field: "$["}, cfg)
+	require.ErrorIs(t, err, source.ErrInvalidSort)
+}
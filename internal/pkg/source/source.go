@@ -15,6 +15,10 @@ import (
 
 const (
 	maxLineSize = 8 * 1024 * 1024
+	// maxMultilineLines and maxLineSize cap an entry that is joined from
+	// several lines, so an unclosed JSON object doesn't swallow the rest of
+	// the log.
+	maxMultilineLines = 1000
 
 	temporaryFilePattern = "jlv-*.log"
 
@@ -22,8 +26,30 @@ const (
 	// ErrInvalidFilter marks a filter term that the user can fix by
 	// retyping it. Unlike I/O errors it is not fatal for the application.
 	ErrInvalidFilter semerr.Error = "invalid filter"
+	// ErrInvalidSort marks a sort by an unknown column or an invalid JSONPath.
+	ErrInvalidSort semerr.Error = "invalid sort"
+	// ErrInvalidHighlight marks a highlight rule with an invalid term or
+	// JSONPath.
+	ErrInvalidHighlight semerr.Error = "invalid highlight"
+	// ErrInvalidTemplate marks a column with an invalid template.
+	ErrInvalidTemplate semerr.Error = "invalid template"
+	// ErrInvalidInput marks a log that doesn't match the input format.
+	ErrInvalidInput semerr.Error = "invalid input"
+
+	// errTrailingData marks a JSON value that is followed by other data.
+	errTrailingData semerr.Error = "data after the JSON value"
 )
 
+// ValidateConfig checks parts of the config that are prepared by the
+// source: highlight rules and templates of columns.
+func ValidateConfig(cfg *config.Config) error {
+	if _, err := NewHighlighter(cfg.Highlights, cfg); err != nil {
+		return err
+	}
+
+	return ValidateTemplates(cfg)
+}
+
 type Source struct {
 	// Seeker is used to do random access reads from the file.
 	Seeker *os.File
@@ -41,6 +67,24 @@ type Source struct {
 	maxSize int64
 	// temporaryFiles to remove at the end.
 	temporaryFiles []string
+	// multiline rules join several lines into a single entry. They are nil
+	// if each line is a separate entry.
+	multiline *multilineRules
+	// pending are lines that have been read ahead while assembling
+	// a multiline entry. The first of them starts the next entry.
+	pending []rawLine
+	// format of the input.
+	format config.InputFormat
+	// jsonArray is a state of reading the input in the JSON format.
+	jsonArray jsonArrayInput
+	// csv is a state of reading the input in the CSV or TSV format.
+	csv csvInput
+}
+
+// rawLine is a line of the file together with its position.
+type rawLine struct {
+	entry LazyLogEntry
+	data  []byte
 }
 
 // Close implements io.Closer.
@@ -73,6 +117,11 @@ func File(name string, cfg *config.Config) (*Source, error) {
 		name:    name,
 	}
 
+	err = source.configure(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	source.file, err = os.Open(name)
 	if err != nil {
 		return nil, fmt.Errorf("opening: %w", err)
@@ -100,6 +149,11 @@ func Reader(input io.Reader, cfg *config.Config) (*Source, error) {
 		maxSize: int64(cfg.MaxFileSizeBytes),
 	}
 
+	err = source.configure(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	// We will write the as read to a temp file.  Seek against the temp file.
 	source.file, err = os.CreateTemp(
 		"", // Default directory for temporary files.
@@ -126,6 +180,25 @@ func Reader(input io.Reader, cfg *config.Config) (*Source, error) {
 	return source, nil
 }
 
+// configure applies the settings of reading entries.
+func (s *Source) configure(cfg *config.Config) error {
+	var err error
+
+	s.multiline, err = newMultilineRules(cfg.Multiline)
+	if err != nil {
+		return err
+	}
+
+	s.csv.decoder, err = newInputDecoder(cfg.InputFormat)
+	if err != nil {
+		return err
+	}
+
+	s.format = cfg.InputFormat
+
+	return nil
+}
+
 func (s *Source) ParseLogEntries() (LazyLogEntries, error) {
 	logEntries := make([]LazyLogEntry, 0, initialLogSize)
 	for {
@@ -145,32 +218,116 @@ func (s *Source) ParseLogEntries() (LazyLogEntries, error) {
 	return LazyLogEntries{
 		Seeker:  s.Seeker,
 		Entries: logEntries,
+		Loaded:  true,
 	}, nil
 }
 
+// CanFollow returns true if new entries can be appended to the source. Only
+// files with an entry per line can be followed.
 func (s *Source) CanFollow() bool {
+	if s.format != "" && s.format != config.InputFormatLines {
+		return false
+	}
+
 	return len(s.name) != 0
 }
 
 // readLogEntry reads the next LazyLogEntry from the file.
+//
+// If multiline rules are configured, the entry spans all lines that continue
+// it. The line that follows the entry is kept until the next call, so an
+// entry is returned only when the next one starts or the end of the file is
+// reached. Lines appended to the followed file later are not joined with
+// the entries that have already been returned.
 func (s *Source) readLogEntry() (LazyLogEntry, error) {
+	switch s.format {
+	case config.InputFormatJSON:
+		return s.readJSONArrayElement()
+	case config.InputFormatCSV, config.InputFormatTSV:
+		return s.readCSVRecord()
+	}
+
+	if s.multiline == nil {
+		line, err := s.readLine()
+
+		return line.entry, err
+	}
+
+	first, err := s.nextLine()
+	if err != nil {
+		return LazyLogEntry{}, err
+	}
+
+	entry := first.entry
+	multilineEntry := s.multiline.newEntry(first.data)
+	size := len(first.data)
+
+	var joined []rawLine
+
+	for {
+		next, err := s.nextLine()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return entry, nil
+			}
+
+			return LazyLogEntry{}, err
+		}
+
+		if !multilineEntry.continues(next.data) {
+			s.pending = append([]rawLine{next}, s.pending...)
+
+			return entry, nil
+		}
+
+		if len(joined) >= maxMultilineLines || size+len(next.data) > maxLineSize {
+			// The entry is too long, so its lines are separate entries.
+			s.pending = append(append(joined, next), s.pending...)
+
+			return first.entry, nil
+		}
+
+		multilineEntry.append(next.data)
+		joined = append(joined, next)
+		size += len(next.data)
+
+		// Empty lines in between are skipped, but they are still a part of
+		// the entry, so the entry spans up to the end of the next line.
+		entry.length = int(next.entry.offset-entry.offset) + next.entry.length
+	}
+}
+
+// nextLine returns the line that has been read ahead or reads the next one.
+func (s *Source) nextLine() (rawLine, error) {
+	if len(s.pending) == 0 {
+		return s.readLine()
+	}
+
+	line := s.pending[0]
+	s.pending = s.pending[1:]
+
+	return line, nil
+}
+
+// readLine reads the next non-empty line from the file.
+func (s *Source) readLine() (rawLine, error) {
 	for {
 		if s.reader == nil {
 			// If we can't follow the file, or we have reached the max size, we are done.
 			if !s.CanFollow() || s.offset >= s.maxSize {
-				return LazyLogEntry{}, io.EOF
+				return rawLine{}, io.EOF
 			}
 
 			// Has the file size changed since we last looked?
 			info, err := os.Stat(s.name)
 			if err != nil || s.prevFollowSize == info.Size() {
-				return LazyLogEntry{}, io.EOF
+				return rawLine{}, io.EOF
 			}
 
 			if info.Size() < s.offset {
 				// The file has been truncated or rolled over, all previous line
 				// offsets are invalid. We can't recover from this.
-				return LazyLogEntry{}, ErrFileTruncated
+				return rawLine{}, ErrFileTruncated
 			}
 
 			s.prevFollowSize = info.Size()
@@ -186,7 +343,7 @@ func (s *Source) readLogEntry() (LazyLogEntry, error) {
 				s.reader = nil
 			}
 
-			return LazyLogEntry{}, err
+			return rawLine{}, err
 		}
 
 		length := len(line)
@@ -194,9 +351,12 @@ func (s *Source) readLogEntry() (LazyLogEntry, error) {
 		s.offset += int64(length)
 
 		if len(bytes.TrimSpace(line)) != 0 {
-			return LazyLogEntry{
-				offset: offset,
-				length: length,
+			return rawLine{
+				entry: LazyLogEntry{
+					offset: offset,
+					length: length,
+				},
+				data: line,
 			}, nil
 		}
 	}
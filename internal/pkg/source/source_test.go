@@ -3,6 +3,7 @@ package source_test
 import (
 	"bytes"
 	"os"
+	"slices"
 	"strings"
 	"testing"
 	"testing/iotest"
@@ -87,6 +88,142 @@ func TestParseLogEntries(t *testing.T) {
 	})
 }
 
+func TestParseLogEntriesMultiline(t *testing.T) {
+	t.Parallel()
+
+	const stackTrace = `{"message":"first"}
+panic: runtime error
+
+goroutine 1 [running]:
+	main.main()
+		/app/main.go:10 +0x1d
+{"message":"second"}
+`
+
+	const prettyJSON = `{
+  "message": "first {",
+  "nested": {"key": "}"}
+}
+{"message":"second"}
+{
+  "message": "third"
+}
+`
+
+	testCases := [...]struct {
+		Name      string
+		Input     string
+		Multiline config.Multiline
+		Expected  []string
+	}{{
+		Name:      "disabled",
+		Input:     "first\n  second\n",
+		Multiline: config.Multiline{},
+		Expected:  []string{"first\n", "  second\n"},
+	}, {
+		Name:      "indentation",
+		Input:     stackTrace,
+		Multiline: config.Multiline{Indentation: true},
+		Expected: []string{
+			"{\"message\":\"first\"}\n",
+			"panic: runtime error\n",
+			"goroutine 1 [running]:\n\tmain.main()\n\t\t/app/main.go:10 +0x1d\n",
+			"{\"message\":\"second\"}\n",
+		},
+	}, {
+		Name:      "start_pattern",
+		Input:     stackTrace,
+		Multiline: config.Multiline{StartPattern: "^{"},
+		Expected: []string{
+			"{\"message\":\"first\"}\npanic: runtime error\n\ngoroutine 1 [running]:\n\tmain.main()\n\t\t/app/main.go:10 +0x1d\n",
+			"{\"message\":\"second\"}\n",
+		},
+	}, {
+		Name:      "json",
+		Input:     prettyJSON,
+		Multiline: config.Multiline{JSON: true},
+		Expected: []string{
+			"{\n  \"message\": \"first {\",\n  \"nested\": {\"key\": \"}\"}\n}\n",
+			"{\"message\":\"second\"}\n",
+			"{\n  \"message\": \"third\"\n}\n",
+		},
+	}, {
+		Name:      "json_unclosed",
+		Input:     "{\n\"message\": \"first\"\n",
+		Multiline: config.Multiline{JSON: true},
+		Expected:  []string{"{\n\"message\": \"first\"\n"},
+	}, {
+		// The truncated line would join all following lines, so the lines
+		// are separate entries once the cap is reached.
+		Name:      "json_truncated",
+		Input:     "{\"message\":\"a\n" + strings.Repeat("{\"message\":\"b\"}\n", 1500),
+		Multiline: config.Multiline{JSON: true},
+		Expected:  append([]string{"{\"message\":\"a\n"}, slices.Repeat([]string{"{\"message\":\"b\"}\n"}, 1500)...),
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.GetDefaultConfig()
+			cfg.Multiline = testCase.Multiline
+
+			inputSource, err := source.Reader(strings.NewReader(testCase.Input), cfg)
+			require.NoError(t, err)
+
+			t.Cleanup(func() { assert.NoError(t, inputSource.Close()) })
+
+			logEntries, err := inputSource.ParseLogEntries()
+			require.NoError(t, err)
+
+			actual := make([]string, 0, logEntries.Len())
+
+			for i := range logEntries.Len() {
+				entry := logEntries.LogEntry(cfg, i)
+				require.NoError(t, entry.Error)
+
+				assert.Equal(t, i, entry.Index)
+
+				actual = append(actual, string(entry.Line))
+			}
+
+			assert.Equal(t, testCase.Expected, actual)
+		})
+	}
+
+	t.Run("json_parsed", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.GetDefaultConfig()
+		cfg.Multiline = config.Multiline{JSON: true}
+
+		inputSource, err := source.Reader(strings.NewReader(prettyJSON), cfg)
+		require.NoError(t, err)
+
+		t.Cleanup(func() { assert.NoError(t, inputSource.Close()) })
+
+		logEntries, err := inputSource.ParseLogEntries()
+		require.NoError(t, err)
+		require.Equal(t, 3, logEntries.Len())
+
+		assert.Contains(t, logEntries.Row(cfg, 0), "first {")
+		assert.Contains(t, logEntries.Row(cfg, 2), "third")
+	})
+
+	t.Run("invalid_start_pattern", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.GetDefaultConfig()
+		cfg.Multiline = config.Multiline{StartPattern: "("}
+
+		_, err := source.Reader(strings.NewReader(""), cfg)
+		require.Error(t, err)
+
+		_, err = source.File(tests.RequireCreateFile(t, nil), cfg)
+		require.Error(t, err)
+	})
+}
+
 func TestParseLogEntriesFromReaderLimited(t *testing.T) {
 	t.Parallel()
 
@@ -174,3 +311,17 @@ func TestReaderTemporaryFilesDeleted(t *testing.T) {
 	require.Error(t, err)
 	assert.True(t, os.IsNotExist(err), err)
 }
+
+func TestValidateConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	require.NoError(t, source.ValidateConfig(cfg))
+
+	cfg.Highlights = []config.Highlight{{Term: "/(/"}}
+	require.ErrorIs(t, source.ValidateConfig(cfg), source.ErrInvalidHighlight)
+
+	cfg.Highlights = nil
+	cfg.Fields = append(cfg.Fields, config.Field{Title: "Request", Kind: config.FieldKindAny, Template: "{{ .method "})
+	require.ErrorIs(t, source.ValidateConfig(cfg), source.ErrInvalidTemplate)
+}
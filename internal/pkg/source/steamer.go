@@ -21,14 +21,16 @@ func (s *Source) StartStreaming(ctx context.Context, send func(msg LazyLogEntrie
 	logEntriesLock := sync.Mutex{}
 	logEntries := make([]LazyLogEntry, 0, initialLogSize)
 	eofEvent := make(chan struct{}, 1)
+	loaded := false
 
 	// Load log entries async..
-	go s.readLogEntries(ctx, send, &logEntriesLock, &logEntries, eofEvent)
+	go s.readLogEntries(ctx, send, &logEntriesLock, &logEntries, &loaded, eofEvent)
 
 	// periodically send new log entries to the program.
 	go func() {
 		ticker := time.NewTicker(RefreshInterval)
 		lastLen := -1
+		lastLoaded := false
 		defer ticker.Stop()
 
 		sendUpdates := func() {
@@ -37,15 +39,18 @@ func (s *Source) StartStreaming(ctx context.Context, send func(msg LazyLogEntrie
 			logEntriesLock.Lock()
 			logEntriesClone := make([]LazyLogEntry, len(logEntries))
 			copy(logEntriesClone, logEntries)
+			nextLoaded := loaded
 			logEntriesLock.Unlock()
 
 			nextLen := len(logEntriesClone)
-			if lastLen != nextLen {
+			if lastLen != nextLen || lastLoaded != nextLoaded {
 				send(LazyLogEntries{
 					Seeker:  s.Seeker,
 					Entries: logEntriesClone,
+					Loaded:  nextLoaded,
 				}, nil)
 				lastLen = nextLen
+				lastLoaded = nextLoaded
 			}
 		}
 
@@ -69,6 +74,7 @@ func (s *Source) readLogEntries(
 	send func(msg LazyLogEntries, err error),
 	logEntriesLock *sync.Mutex,
 	logEntries *[]LazyLogEntry,
+	loaded *bool,
 	eofEvent chan struct{},
 ) {
 	defer func() {
@@ -85,6 +91,10 @@ func (s *Source) readLogEntries(
 		entry, err := s.readLogEntry()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
+				logEntriesLock.Lock()
+				*loaded = true
+				logEntriesLock.Unlock()
+
 				if !s.CanFollow() {
 					return
 				}
@@ -52,6 +52,7 @@ func TestStartStreamingEndOfFile(t *testing.T) {
 
 		require.Equal(t, 1, msg.Len())
 		assert.Contains(t, msg.Row(cfg, 0), entry)
+		assert.True(t, msg.Loaded)
 	case <-ctx.Done():
 		t.Fatal(ctx.Err())
 	}
@@ -99,6 +100,7 @@ func TestStartStreamingUpdates(t *testing.T) {
 		case msg := <-entries:
 			require.Equalf(t, i+1, msg.Len(), "iteration %d", i)
 			assert.Containsf(t, msg.Row(cfg, 0), entry, "iteration %d", i)
+			assert.Falsef(t, msg.Loaded, "iteration %d", i)
 		case <-ctx.Done():
 			t.Fatal(ctx.Err())
 		}
@@ -0,0 +1,191 @@
+package source
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/docker/go-units"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+)
+
+// templates cache parsed templates of columns, because columns are
+// rendered for every entry.
+var (
+	templatesLock sync.Mutex
+	templates     = map[string]*template.Template{}
+)
+
+// templateFuncs are helpers that templates of columns can call.
+var templateFuncs = template.FuncMap{
+	"default":  templateDefault,
+	"truncate": templateTruncate,
+	"upper":    func(value any) string { return strings.ToUpper(templateText(value)) },
+	"lower":    func(value any) string { return strings.ToLower(templateText(value)) },
+	"duration": templateDuration,
+	"bytes":    templateBytes,
+	"text":     templateText,
+}
+
+// ValidateTemplates parses templates of columns of the config, its views
+// and profiles.
+func ValidateTemplates(cfg *config.Config) error {
+	fields := cfg.Fields
+
+	for _, view := range cfg.Views {
+		fields = append(fields[:len(fields):len(fields)], view.Fields...)
+	}
+
+	for _, profile := range cfg.Profiles {
+		fields = append(fields[:len(fields):len(fields)], profile.Fields...)
+	}
+
+	for _, field := range fields {
+		if field.Template == "" {
+			continue
+		}
+
+		if _, err := parseTemplate(field.Template); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidTemplate, field.Title, err)
+		}
+	}
+
+	return nil
+}
+
+func parseTemplate(text string) (*template.Template, error) {
+	templatesLock.Lock()
+	defer templatesLock.Unlock()
+
+	if parsed, ok := templates[text]; ok {
+		return parsed, nil
+	}
+
+	parsed, err := template.New("field").Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	printAsText(parsed.Root)
+
+	templates[text] = parsed
+
+	return parsed, nil
+}
+
+// executeTemplate renders the template with the JSON object. Missing keys
+// are rendered empty.
+func executeTemplate(parsedLine any, text string) (string, bool) {
+	parsed, err := parseTemplate(text)
+	if err != nil {
+		return "", false
+	}
+
+	var rendered strings.Builder
+
+	if err := parsed.Execute(&rendered, parsedLine); err != nil {
+		return "", false
+	}
+
+	return rendered.String(), true
+}
+
+// printAsText pipes printed values of the template to the "text" helper,
+// so missing keys are rendered empty instead of "<no value>" and objects
+// are rendered as JSON.
+func printAsText(node parse.Node) {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return
+		}
+
+		for _, child := range node.Nodes {
+			printAsText(child)
+		}
+	case *parse.ActionNode:
+		// Assignments are not printed.
+		if len(node.Pipe.Decl) > 0 {
+			return
+		}
+
+		node.Pipe.Cmds = append(node.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      node.Pos,
+			Args:     []parse.Node{parse.NewIdentifier("text").SetPos(node.Pos)},
+		})
+	case *parse.IfNode:
+		printAsText(node.List)
+		printAsText(node.ElseList)
+	case *parse.RangeNode:
+		printAsText(node.List)
+		printAsText(node.ElseList)
+	case *parse.WithNode:
+		printAsText(node.List)
+		printAsText(node.ElseList)
+	}
+}
+
+// templateText returns the text of the value, objects and arrays are
+// rendered as JSON.
+func templateText(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case map[string]any, []any:
+		return formatJSON(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// templateDefault returns the fallback if the value is missing or empty:
+// {{ .user | default "anonymous" }}.
+func templateDefault(fallback any, value any) any {
+	if templateText(value) == "" {
+		return fallback
+	}
+
+	return value
+}
+
+// templateTruncate cuts the text to the number of characters:
+// {{ .path | truncate 20 }}.
+func templateTruncate(length int, value any) string {
+	runes := []rune(templateText(value))
+	if length <= 0 || len(runes) <= length {
+		return string(runes)
+	}
+
+	return string(runes[:length-1]) + "…"
+}
+
+// templateDuration formats a number in the unit or a Go duration:
+// {{ .elapsed_ms | duration "ms" }}.
+func templateDuration(unit string, value any) string {
+	text := templateText(value)
+
+	duration, ok := parseDuration(text, config.Field{Unit: unit})
+	if !ok {
+		return text
+	}
+
+	return formatDuration(duration)
+}
+
+// templateBytes formats a number of bytes: {{ .size | bytes }}.
+func templateBytes(value any) string {
+	text := templateText(value)
+
+	size, ok := parseBytes(text)
+	if !ok {
+		return text
+	}
+
+	return units.BytesSize(size)
+}
@@ -0,0 +1,169 @@
+package source_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+	"github.com/hedhyw/json-log-viewer/internal/pkg/source"
+)
+
+func TestFieldTemplate(t *testing.T) {
+	t.Parallel()
+
+	const request = `{"method":"GET","path":"/api/users","status":200,"duration_ms":12,"size":1234567}`
+
+	testCases := [...]struct {
+		Name     string
+		Kind     config.FieldKind
+		Template string
+		JSON     string
+		Expected string
+	}{{
+		Name:     "request",
+		Template: `{{ .method }} {{ .path }} → {{ .status }} ({{ .duration_ms | duration "ms" }})`,
+		JSON:     request,
+		Expected: "GET /api/users → 200 (12ms)",
+	}, {
+		Name:     "large_number",
+		Template: `{{ .size }}`,
+		JSON:     request,
+		Expected: "1234567",
+	}, {
+		Name:     "bytes",
+		Template: `{{ .size | bytes }}`,
+		JSON:     request,
+		Expected: "1.177MiB",
+	}, {
+		Name:     "upper_lower",
+		Template: `{{ .method | lower }} {{ .path | upper }}`,
+		JSON:     request,
+		Expected: "get /API/USERS",
+	}, {
+		Name:     "truncate",
+		Template: `{{ .path | truncate 6 }}`,
+		JSON:     request,
+		Expected: "/api/…",
+	}, {
+		Name:     "default",
+		Template: `{{ .user | default "anonymous" }}`,
+		JSON:     request,
+		Expected: "anonymous",
+	}, {
+		Name:     "missing_key",
+		Template: `{{ .method }} {{ .user }}`,
+		JSON:     request,
+		Expected: "GET",
+	}, {
+		Name:     "missing_key_in_block",
+		Template: `{{ if .method }}{{ .user }}{{ .method }}{{ end }}`,
+		JSON:     request,
+		Expected: "GET",
+	}, {
+		Name:     "no_value_text",
+		Template: `{{ .message }}`,
+		JSON:     `{"message":"<no value>"}`,
+		Expected: "<no value>",
+	}, {
+		Name:     "object",
+		Template: `{{ .http }}`,
+		JSON:     `{"http":{"method":"POST"}}`,
+		Expected: `{"method":"POST"}`,
+	}, {
+		Name:     "assignment",
+		Template: `{{ $method := .method }}{{ $method | lower }}`,
+		JSON:     request,
+		Expected: "get",
+	}, {
+		Name:     "empty",
+		Template: `{{ .user }}`,
+		JSON:     request,
+		Expected: "-",
+	}, {
+		Name:     "execution_error",
+		Template: `{{ .user.name }}`,
+		JSON:     request,
+		Expected: "-",
+	}, {
+		Name:     "nested",
+		Template: `{{ .http.method }} {{ index .http "@path" }}`,
+		JSON:     `{"http":{"method":"POST","@path":"/login"}}`,
+		Expected: "POST /login",
+	}, {
+		Name:     "kind",
+		Kind:     config.FieldKindNumber,
+		Template: `{{ .size }}`,
+		JSON:     request,
+		Expected: "1,234,567",
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			kind := testCase.Kind
+			if kind == "" {
+				kind = config.FieldKindAny
+			}
+
+			cfg := config.GetDefaultConfig()
+			cfg.Fields = []config.Field{{Title: "Request", Kind: kind, Template: testCase.Template}}
+
+			actual := parseTableRow(t, testCase.JSON, cfg)
+			assert.Equal(t, testCase.Expected, actual[0])
+		})
+	}
+}
+
+func TestFieldTemplateFilterSort(t *testing.T) {
+	t.Parallel()
+
+	const input = `{"method":"POST","path":"/b"}
+{"method":"GET","path":"/c"}
+{"method":"GET","path":"/a"}
+`
+
+	cfg := config.GetDefaultConfig()
+	cfg.Fields = []config.Field{{Title: "Request", Kind: config.FieldKindAny, Template: "{{ .method }} {{ .path }}"}}
+
+	entries := requireParseLogEntries(t, input, cfg)
+
+	filtered, err := entries.Filter("/^GET /", "Request", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 2, filtered.Len())
+
+	sorted, err := entries.Sort(config.Sort{Field: "Request"}, cfg)
+	require.NoError(t, err)
+
+	indexes := make([]int, 0, sorted.Len())
+	for _, entry := range sorted.Entries {
+		indexes = append(indexes, entry.Index())
+	}
+
+	assert.Equal(t, []int{2, 1, 0}, indexes)
+}
+
+func TestValidateTemplates(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	require.NoError(t, source.ValidateTemplates(cfg))
+
+	cfg.Fields = append(cfg.Fields, config.Field{Title: "Request", Kind: config.FieldKindAny, Template: "{{ .method | truncate 10 }}"})
+	require.NoError(t, source.ValidateTemplates(cfg))
+
+	cfg.Views = []config.View{{
+		Name:   "requests",
+		Fields: []config.Field{{Title: "Request", Kind: config.FieldKindAny, Template: "{{ .method | unknown }}"}},
+	}}
+	require.ErrorIs(t, source.ValidateTemplates(cfg), source.ErrInvalidTemplate)
+
+	cfg.Views = nil
+	cfg.Profiles = []config.Profile{{
+		Name:   "nginx",
+		Fields: []config.Field{{Title: "Request", Kind: config.FieldKindAny, Template: "{{ .method "}},
+	}}
+	require.ErrorIs(t, source.ValidateTemplates(cfg), source.ErrInvalidTemplate)
+}
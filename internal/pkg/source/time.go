@@ -0,0 +1,98 @@
+package source
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yalp/jsonpath"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+)
+
+// Time returns the time of the entry in the column with the index. It is
+// parsed from the raw value of the entry, because the rendered time could be
+// truncated to seconds or miss the date.
+func (e LogEntry) Time(fieldIndex int, cfg *config.Config) (time.Time, bool) {
+	if fieldIndex < 0 || fieldIndex >= len(cfg.Fields) || fieldIndex >= len(e.Fields) {
+		return time.Time{}, false
+	}
+
+	field := cfg.Fields[fieldIndex]
+	if !field.IsTime() || e.Fields[fieldIndex] == "-" {
+		return time.Time{}, false
+	}
+
+	raw, ok := rawFieldValue(e, field.References)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	// Layouts of rendered times are not used, the raw value is in the
+	// layout of the log.
+	return parseTime(raw, field.Kind, append([]string{time.RFC3339Nano}, cfg.TimeLayouts...))
+}
+
+// rawFieldValue returns the first value of the entry that is found by the
+// references as text.
+func rawFieldValue(entry LogEntry, refs []string) (string, bool) {
+	parsedLine, _, ok := parseJSONObject(entry.Content())
+	if !ok {
+		return "", false
+	}
+
+	for _, ref := range refs {
+		found, err := jsonpath.Read(parsedLine, ref)
+		if err != nil {
+			continue
+		}
+
+		switch value := found.(type) {
+		case string:
+			return value, true
+		case json.Number:
+			return value.String(), true
+		}
+	}
+
+	return "", false
+}
+
+// parseTime parses epoch times of the kind or times in the layouts.
+func parseTime(value string, kind config.FieldKind, layouts []string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		units := map[config.FieldKind]string{
+			config.FieldKindSecondTime: unitSeconds,
+			config.FieldKindMilliTime:  unitMilli,
+			config.FieldKindMicroTime:  unitMicro,
+			config.FieldKindNanoTime:   unitNano,
+		}
+
+		unit, ok := units[kind]
+		if !ok {
+			unit, ok = units[guessTimeFieldKind(value)]
+		}
+
+		if !ok {
+			return time.Time{}, false
+		}
+
+		duration, err := time.ParseDuration(value + unit)
+		if err != nil {
+			return time.Time{}, false
+		}
+
+		return time.Unix(0, 0).Add(duration), true
+	}
+
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,84 @@
+package source_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hedhyw/json-log-viewer/internal/pkg/config"
+)
+
+func TestLogEntryTime(t *testing.T) {
+	t.Parallel()
+
+	const input = `{"time":"2026-01-01T00:00:00.250Z","level":"info","message":"a"}
+{"time":1767225600123456789,"message":"b"}
+{"time":"yesterday","message":"c"}
+{"message":"d"}
+`
+
+	cfg := config.GetDefaultConfig()
+	entries := requireParseLogEntries(t, input, cfg)
+
+	at, ok := entries.LogEntry(cfg, 0).Time(0, cfg)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2026, time.January, 1, 0, 0, 0, 250_000_000, time.UTC).UnixNano(), at.UnixNano())
+
+	// Epoch nanoseconds keep all digits.
+	at, ok = entries.LogEntry(cfg, 1).Time(0, cfg)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2026, time.January, 1, 0, 0, 0, 123_456_789, time.UTC), at.UTC())
+
+	_, ok = entries.LogEntry(cfg, 2).Time(0, cfg)
+	assert.False(t, ok)
+
+	_, ok = entries.LogEntry(cfg, 3).Time(0, cfg)
+	assert.False(t, ok)
+
+	// The message is not a time.
+	_, ok = entries.LogEntry(cfg, 0).Time(2, cfg)
+	assert.False(t, ok)
+
+	t.Run("time_format", func(t *testing.T) {
+		t.Parallel()
+
+		timeFormat := "15:04:05"
+
+		cfg := config.GetDefaultConfig()
+		cfg.Fields = []config.Field{{
+			Title:      "Time",
+			Kind:       config.FieldKindTime,
+			References: []string{"$.time"},
+			TimeFormat: &timeFormat,
+		}}
+
+		entries := requireParseLogEntries(t, `{"time":"12:30:00"}`+"\n", cfg)
+
+		// The value in the format of the column has no date.
+		_, ok := entries.LogEntry(cfg, 0).Time(0, cfg)
+		assert.False(t, ok)
+	})
+}
+
+func TestLogEntryNanoTimePrecision(t *testing.T) {
+	t.Parallel()
+
+	timeFormat := "15:04:05.000000000"
+
+	cfg := config.GetDefaultConfig()
+	cfg.Fields = []config.Field{{
+		Title:      "Time",
+		Kind:       config.FieldKindNanoTime,
+		References: []string{"$.time"},
+		TimeFormat: &timeFormat,
+	}, {
+		Title:      "Raw",
+		Kind:       config.FieldKindAny,
+		References: []string{"$.time"},
+	}}
+
+	entries := requireParseLogEntries(t, `{"time":1700000000123456789}`+"\n", cfg)
+
+	assert.Equal(t, []string{"22:13:20.123456789", "1700000000123456789"}, entries.LogEntry(cfg, 0).Fields)
+}
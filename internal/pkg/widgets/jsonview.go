@@ -16,6 +16,11 @@ func init() {
 	fx.SetCurrentThemeByID(themeFX)
 }
 
+// SetJSONTheme sets the theme of JSON views by its ID, "0" disables colors.
+func SetJSONTheme(id string) {
+	fx.SetCurrentThemeByID(id)
+}
+
 // NewJSONViewModel creates a new JSON view widget if a content is the correct json,
 // or plain text view otherwise.
 func NewJSONViewModel(